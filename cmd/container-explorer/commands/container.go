@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var infoSpec bool
+
+var containerCmd = &cobra.Command{
+	Use:   "container",
+	Short: "Inspect containers",
+}
+
+var containerInfoCmd = &cobra.Command{
+	Use:   "info <container-id>",
+	Short: "Show detailed, docker/podman inspect compatible information about a container",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		explorer, err := newExplorer()
+		if err != nil {
+			return fmt.Errorf("creating explorer %v", err)
+		}
+		defer explorer.Close()
+
+		info, err := explorer.InfoContainer(cmd.Context(), args[0], infoSpec)
+		if err != nil {
+			return fmt.Errorf("getting container info %v", err)
+		}
+
+		out, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling container info %v", err)
+		}
+
+		fmt.Println(string(out))
+		return nil
+	},
+}
+
+func init() {
+	containerInfoCmd.Flags().BoolVar(&infoSpec, "spec", false, "include the OCI runtime spec used to create the container")
+	containerCmd.AddCommand(containerInfoCmd)
+	rootCmd.AddCommand(containerCmd)
+}
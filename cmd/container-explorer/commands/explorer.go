@@ -0,0 +1,43 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/google/container-explorer/explorers"
+	"github.com/google/container-explorer/explorers/docker"
+	"github.com/google/container-explorer/explorers/podman"
+)
+
+// newExplorer picks the docker or podman backend based on which
+// directories exist under root, so the same CLI works against either an
+// offline /var/lib/docker or /var/lib/containers/storage image.
+func newExplorer() (explorers.ContainerExplorer, error) {
+	if isPodmanRoot(imageRoot) {
+		return podman.NewExplorer(imageRoot)
+	}
+	return docker.NewExplorer(imageRoot, containerdRoot, manifestFile, snapshot, platform)
+}
+
+// isPodmanRoot reports whether root looks like a containers/storage
+// directory rather than docker's layout.
+func isPodmanRoot(root string) bool {
+	_, err := os.Stat(filepath.Join(root, "overlay-containers"))
+	return err == nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/container-explorer/explorers"
+	"github.com/spf13/cobra"
+)
+
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Inspect images",
+}
+
+var imageHistoryCmd = &cobra.Command{
+	Use:   "history <ref-or-id>",
+	Short: "Show build history and layer chain for an image, similar to docker history",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		explorer, err := newExplorer()
+		if err != nil {
+			return fmt.Errorf("creating explorer %v", err)
+		}
+		defer explorer.Close()
+
+		images, err := explorer.ListImages(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("listing images %v", err)
+		}
+
+		image, err := findImage(images, args[0])
+		if err != nil {
+			return err
+		}
+
+		printHistory(image)
+		return nil
+	},
+}
+
+// findImage looks up an image by name or digest among images.
+func findImage(images []explorers.Image, ref string) (explorers.Image, error) {
+	for _, image := range images {
+		if image.Name == ref || strings.HasSuffix(string(image.Target.Digest), ref) {
+			return image, nil
+		}
+	}
+	return explorers.Image{}, fmt.Errorf("image %q not found", ref)
+}
+
+func printHistory(image explorers.Image) {
+	fmt.Printf("IMAGE\t\tCREATED\t\tCREATED BY\t\tSIZE\t\tCOMMENT\n")
+	for _, h := range image.History {
+		layer := h.LayerDigest
+		if layer == "" {
+			layer = "<missing>"
+		}
+		fmt.Printf("%s\t%s\t%s\t%d\t%s\n", layer, h.Created.Format("2006-01-02T15:04:05"), h.CreatedBy, h.Size, h.Comment)
+	}
+}
+
+func init() {
+	imageCmd.AddCommand(imageHistoryCmd)
+	rootCmd.AddCommand(imageCmd)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package commands implements the container-explorer CLI.
+package commands
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	imageRoot      string
+	containerdRoot string
+	manifestFile   string
+	snapshot       string
+	platform       string
+	debug          bool
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "container-explorer",
+	Short: "Inspect container and image metadata from an offline storage directory",
+}
+
+// Execute runs the container-explorer CLI.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func init() {
+	cobra.OnInitialize(func() {
+		if debug {
+			log.SetLevel(log.DebugLevel)
+		}
+	})
+
+	rootCmd.PersistentFlags().StringVar(&imageRoot, "root", "/var/lib/docker", "docker/podman root directory")
+	rootCmd.PersistentFlags().StringVar(&containerdRoot, "containerd-root", "/var/lib/containerd", "containerd root directory")
+	rootCmd.PersistentFlags().StringVar(&manifestFile, "manifest", "/var/lib/containerd/io.containerd.metadata.v1.bolt/meta.db", "containerd manifest database file")
+	rootCmd.PersistentFlags().StringVar(&snapshot, "snapshot", "overlayfs", "containerd snapshotter name")
+	rootCmd.PersistentFlags().StringVar(&platform, "platform", "", "platform (os/arch, e.g. linux/amd64) to resolve multi-arch image manifests against; defaults to the first listed manifest")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug logging")
+}
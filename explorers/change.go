@@ -0,0 +1,47 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explorers
+
+// ChangeKind describes the type of modification DiffContainer found for a
+// given path, matching the set `docker diff` / libpod's diff.go report.
+type ChangeKind int
+
+const (
+	ChangeModify ChangeKind = iota
+	ChangeAdd
+	ChangeDelete
+)
+
+// String returns the single letter docker diff uses for a ChangeKind (A, M
+// or D).
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdd:
+		return "A"
+	case ChangeDelete:
+		return "D"
+	default:
+		return "M"
+	}
+}
+
+// Change describes a single path that differs between a container's
+// writable layer and its image.
+type Change struct {
+	Path string
+	Kind ChangeKind
+}
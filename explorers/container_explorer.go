@@ -0,0 +1,60 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explorers
+
+import "context"
+
+// ContainerExplorer is implemented by each supported container runtime
+// backend (docker, podman) to provide a uniform view over
+// container storage regardless of which runtime produced it on disk.
+type ContainerExplorer interface {
+	// SnapshotRoot returns the snapshot root directory for snapshotter.
+	SnapshotRoot(snapshotter string) string
+
+	// ListNamespaces returns the namespaces known to the backend.
+	ListNamespaces(ctx context.Context) ([]string, error)
+
+	// ListContainers returns the containers known to the backend.
+	ListContainers(ctx context.Context) ([]Container, error)
+
+	// ListImages returns the images known to the backend.
+	ListImages(ctx context.Context) ([]Image, error)
+
+	// ListContent returns the content blobs known to the backend.
+	ListContent(ctx context.Context) ([]Content, error)
+
+	// ListSnapshots returns the snapshots known to the backend.
+	ListSnapshots(ctx context.Context) ([]SnapshotKeyInfo, error)
+
+	// InfoContainer returns detailed, docker/podman inspect-like
+	// information about a single container. When spec is true the OCI
+	// runtime spec is included in the result.
+	InfoContainer(ctx context.Context, containerid string, spec bool) (interface{}, error)
+
+	// DiffContainer returns the paths that differ between containerid's
+	// writable layer and the image it was created from.
+	DiffContainer(ctx context.Context, containerid string) ([]Change, error)
+
+	// MountContainer mounts a container's filesystem to mountpoint.
+	MountContainer(ctx context.Context, containerid string, mountpoint string) error
+
+	// MountAllContainers mounts all containers under mountpoint.
+	MountAllContainers(ctx context.Context, mountpoint string, skipsupportcontainers bool) error
+
+	// Close releases internal resources held by the backend.
+	Close() error
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explorers
+
+import "time"
+
+// ContainerInfo is container-explorer's docker/podman "inspect" compatible
+// view of a single container. It combines the on-disk container
+// configuration, the resolved graph-driver mount points and, when
+// requested, the OCI runtime spec used to create the container.
+//
+// Field names intentionally mirror `docker inspect` / `podman inspect` so
+// downstream tooling built against those formats can consume it unchanged.
+type ContainerInfo struct {
+	ID              string          `json:"Id"`
+	Created         time.Time       `json:"Created"`
+	Path            string          `json:"Path"`
+	Args            []string        `json:"Args"`
+	State           ContainerState  `json:"State"`
+	Image           string          `json:"Image"`
+	ResolvConfPath  string          `json:"ResolvConfPath"`
+	HostnamePath    string          `json:"HostnamePath"`
+	HostsPath       string          `json:"HostsPath"`
+	LogPath         string          `json:"LogPath"`
+	Name            string          `json:"Name"`
+	RestartCount    int             `json:"RestartCount"`
+	Driver          string          `json:"Driver"`
+	Platform        string          `json:"Platform"`
+	MountLabel      string          `json:"MountLabel"`
+	ProcessLabel    string          `json:"ProcessLabel"`
+	AppArmorProfile string          `json:"AppArmorProfile"`
+	GraphDriver     GraphDriverData `json:"GraphDriver"`
+	NetworkSettings interface{}     `json:"NetworkSettings,omitempty"`
+	// Spec carries the OCI runtime spec (containers/<id>/config.json) when
+	// it was present on disk and the caller asked for it.
+	Spec interface{} `json:"Spec,omitempty"`
+}
+
+// ContainerState mirrors docker/podman's container state block.
+type ContainerState struct {
+	Running    bool      `json:"Running"`
+	Paused     bool      `json:"Paused"`
+	Restarting bool      `json:"Restarting"`
+	OOMKilled  bool      `json:"OOMKilled"`
+	Dead       bool      `json:"Dead"`
+	Pid        int       `json:"Pid"`
+	ExitCode   int       `json:"ExitCode"`
+	Error      string    `json:"Error"`
+	StartedAt  time.Time `json:"StartedAt"`
+	FinishedAt time.Time `json:"FinishedAt"`
+}
+
+// GraphDriverData mirrors docker inspect's GraphDriver block: the name of
+// the storage driver plus its driver-specific mount point data.
+type GraphDriverData struct {
+	Name string            `json:"Name"`
+	Data map[string]string `json:"Data"`
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import "time"
+
+// Config holds the subset of docker's container.Config that
+// container-explorer cares about.
+//
+// Reference to moby's container.Config https://github.com/moby/moby/blob/master/api/types/container/config.go
+type Config struct {
+	Hostname     string              `json:"Hostname"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	Cmd          []string            `json:"Cmd,omitempty"`
+	Entrypoint   []string            `json:"Entrypoint,omitempty"`
+	Image        string              `json:"Image,omitempty"`
+	Volumes      map[string]struct{} `json:"Volumes,omitempty"`
+	WorkingDir   string              `json:"WorkingDir,omitempty"`
+	Labels       map[string]string   `json:"Labels,omitempty"`
+}
+
+// State holds the runtime state of a container.
+//
+// Reference to moby's container.State https://github.com/moby/moby/blob/master/container/state.go
+type State struct {
+	Running    bool      `json:"Running"`
+	Paused     bool      `json:"Paused"`
+	Restarting bool      `json:"Restarting"`
+	OOMKilled  bool      `json:"OOMKilled"`
+	Dead       bool      `json:"Dead"`
+	Pid        int       `json:"Pid"`
+	ExitCode   int       `json:"ExitCode"`
+	Error      string    `json:"Error"`
+	StartedAt  time.Time `json:"StartedAt"`
+	FinishedAt time.Time `json:"FinishedAt"`
+}
+
+// NetworkSettings holds the subset of docker's network.Settings that
+// container-explorer surfaces as-is through InfoContainer.
+type NetworkSettings struct {
+	Bridge     string                 `json:"Bridge,omitempty"`
+	SandboxID  string                 `json:"SandboxID,omitempty"`
+	Ports      map[string]interface{} `json:"Ports,omitempty"`
+	Networks   map[string]interface{} `json:"Networks,omitempty"`
+	IPAddress  string                 `json:"IPAddress,omitempty"`
+}
+
+// ConfigFile maps the on-disk layout of docker's config.v2.json file.
+//
+// Reference to moby's container.Container struct https://github.com/moby/moby/blob/master/container/container.go
+type ConfigFile struct {
+	ID              string           `json:"ID"`
+	Created         time.Time        `json:"Created"`
+	Path            string           `json:"Path"`
+	Args            []string         `json:"Args"`
+	Config          Config           `json:"Config"`
+	Image           string           `json:"Image"`
+	NetworkSettings *NetworkSettings `json:"NetworkSettings,omitempty"`
+	ResolvConfPath  string           `json:"ResolvConfPath"`
+	HostnamePath    string           `json:"HostnamePath"`
+	HostsPath       string           `json:"HostsPath"`
+	LogPath         string           `json:"LogPath"`
+	Name            string           `json:"Name"`
+	RestartCount    int              `json:"RestartCount"`
+	Driver          string           `json:"Driver"`
+	Platform        string           `json:"Platform"`
+	MountLabel      string           `json:"MountLabel"`
+	ProcessLabel    string           `json:"ProcessLabel"`
+	AppArmorProfile string           `json:"AppArmorProfile"`
+	State           State            `json:"State"`
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/google/container-explorer/explorers"
+	"github.com/google/container-explorer/explorers/graphdriver"
+)
+
+// DiffContainer returns the paths that differ between containerid's
+// writable layer and the image it was created from, the same information
+// `docker diff` / libpod's diff.go report. Because it only walks the
+// container's upperdir this works entirely offline, without a live
+// container runtime.
+func (e *explorer) DiffContainer(ctx context.Context, containerid string) ([]explorers.Change, error) {
+	container, err := e.getContainer(ctx, containerid)
+	if err != nil {
+		return nil, fmt.Errorf("getting container %v", err)
+	}
+
+	driver, err := graphdriver.Get(container.Driver)
+	if err != nil {
+		return nil, fmt.Errorf("looking up graph driver %v", err)
+	}
+
+	lowerdirs, err := driver.Layers(e.root, containerid)
+	if err != nil {
+		return nil, fmt.Errorf("resolving container layers %v", err)
+	}
+
+	upperdir, err := driver.UpperDir(e.root, containerid)
+	if err != nil {
+		return nil, fmt.Errorf("resolving container upperdir %v", err)
+	}
+
+	var changes []explorers.Change
+
+	err = filepath.Walk(upperdir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == upperdir {
+			return nil
+		}
+
+		relpath, err := filepath.Rel(upperdir, path)
+		if err != nil {
+			return fmt.Errorf("resolving relative path for %s %v", path, err)
+		}
+		changepath := string(filepath.Separator) + relpath
+
+		if isWhiteout(info) {
+			changes = append(changes, explorers.Change{Path: changepath, Kind: explorers.ChangeDelete})
+			return nil
+		}
+
+		kind := explorers.ChangeAdd
+		if existsInAny(lowerdirs, relpath) || (info.IsDir() && isOpaque(path)) {
+			kind = explorers.ChangeModify
+		}
+		changes = append(changes, explorers.Change{Path: changepath, Kind: kind})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking container upperdir %s %v", upperdir, err)
+	}
+
+	return changes, nil
+}
+
+// isWhiteout reports whether info is an overlayfs whiteout marker: a
+// character device with major/minor 0/0 recording that the path was
+// deleted in this layer.
+func isWhiteout(info os.FileInfo) bool {
+	if info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	return stat.Rdev == 0
+}
+
+// isOpaque reports whether the directory at path carries overlayfs's
+// "trusted.overlay.opaque" xattr, meaning it fully masks the same
+// directory in every lower layer.
+func isOpaque(path string) bool {
+	buf := make([]byte, 8)
+	n, err := syscall.Getxattr(path, "trusted.overlay.opaque", buf)
+	if err != nil {
+		return false
+	}
+	return string(buf[:n]) == "y"
+}
+
+// existsInAny reports whether relpath exists under any of dirs.
+func existsInAny(dirs []string, relpath string) bool {
+	for _, dir := range dirs {
+		if fileExists(filepath.Join(dir, relpath)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/google/container-explorer/explorers"
+)
+
+func TestExistsInAny(t *testing.T) {
+	dir1, err := ioutil.TempDir("", "diff-test-")
+	if err != nil {
+		t.Fatalf("creating temp dir %v", err)
+	}
+	defer os.RemoveAll(dir1)
+
+	dir2, err := ioutil.TempDir("", "diff-test-")
+	if err != nil {
+		t.Fatalf("creating temp dir %v", err)
+	}
+	defer os.RemoveAll(dir2)
+
+	if err := ioutil.WriteFile(filepath.Join(dir2, "present"), nil, 0644); err != nil {
+		t.Fatalf("writing fixture file %v", err)
+	}
+
+	if !existsInAny([]string{dir1, dir2}, "present") {
+		t.Error("existsInAny() = false, want true for a path present in the second directory")
+	}
+	if existsInAny([]string{dir1, dir2}, "missing") {
+		t.Error("existsInAny() = true, want false for a path present in neither directory")
+	}
+}
+
+// TestDiffContainer exercises DiffContainer's Added/Modified/Deleted
+// classification against a fixture overlay2 upperdir/lowerdir pair:
+// a file only in the upperdir (Added), a file present in both (Modified),
+// and an overlayfs whiteout char device (Deleted).
+func TestDiffContainer(t *testing.T) {
+	root, err := ioutil.TempDir("", "diff-test-")
+	if err != nil {
+		t.Fatalf("creating temp dir %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	containerid := "abc123"
+
+	containerdir := filepath.Join(root, containersDirName, containerid)
+	if err := os.MkdirAll(containerdir, 0755); err != nil {
+		t.Fatalf("creating container dir %v", err)
+	}
+	configdata := []byte(`{"ID": "abc123", "Driver": "overlay2"}`)
+	if err := ioutil.WriteFile(filepath.Join(containerdir, configV2Filename), configdata, 0644); err != nil {
+		t.Fatalf("writing container config %v", err)
+	}
+
+	mountsdir := filepath.Join(root, "image", "overlay2", "layerdb", "mounts", containerid)
+	if err := os.MkdirAll(mountsdir, 0755); err != nil {
+		t.Fatalf("creating mounts dir %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(mountsdir, "mount-id"), []byte("mid1"), 0644); err != nil {
+		t.Fatalf("writing mount-id %v", err)
+	}
+
+	lowerdir := filepath.Join(root, "overlay2", "lower1")
+	if err := os.MkdirAll(lowerdir, 0755); err != nil {
+		t.Fatalf("creating lowerdir %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(lowerdir, "shared.txt"), nil, 0644); err != nil {
+		t.Fatalf("writing lowerdir fixture file %v", err)
+	}
+
+	upperdir := filepath.Join(root, "overlay2", "mid1", "diff")
+	if err := os.MkdirAll(upperdir, 0755); err != nil {
+		t.Fatalf("creating upperdir %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "overlay2", "mid1", "lower"), []byte("lower1"), 0644); err != nil {
+		t.Fatalf("writing lower file %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(upperdir, "added.txt"), nil, 0644); err != nil {
+		t.Fatalf("writing added file %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(upperdir, "shared.txt"), nil, 0644); err != nil {
+		t.Fatalf("writing modified file %v", err)
+	}
+
+	haveWhiteout := true
+	if err := syscall.Mknod(filepath.Join(upperdir, "deleted.txt"), syscall.S_IFCHR, 0); err != nil {
+		haveWhiteout = false
+		t.Logf("skipping whiteout fixture, mknod requires root %v", err)
+	}
+
+	e := &explorer{root: root}
+	changes, err := e.DiffContainer(context.Background(), containerid)
+	if err != nil {
+		t.Fatalf("DiffContainer: unexpected error %v", err)
+	}
+
+	got := map[string]explorers.ChangeKind{}
+	for _, c := range changes {
+		got[c.Path] = c.Kind
+	}
+
+	if kind, ok := got["/added.txt"]; !ok || kind != explorers.ChangeAdd {
+		t.Errorf("changes[/added.txt] = %v, %v, want ChangeAdd, true", kind, ok)
+	}
+	if kind, ok := got["/shared.txt"]; !ok || kind != explorers.ChangeModify {
+		t.Errorf("changes[/shared.txt] = %v, %v, want ChangeModify, true", kind, ok)
+	}
+	if haveWhiteout {
+		if kind, ok := got["/deleted.txt"]; !ok || kind != explorers.ChangeDelete {
+			t.Errorf("changes[/deleted.txt] = %v, %v, want ChangeDelete, true", kind, ok)
+		}
+	}
+}
+
+// TestListContent covers ListContent's two fixture shapes: an imagedb
+// content blob and a distribution diffid-by-digest entry, both tagged
+// through repositories.json.
+func TestListContent(t *testing.T) {
+	root, err := ioutil.TempDir("", "diff-test-")
+	if err != nil {
+		t.Fatalf("creating temp dir %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	storagedir := filepath.Join(root, "image", "overlay2")
+
+	contentdir := filepath.Join(storagedir, "imagedb", "content", "sha256")
+	if err := os.MkdirAll(contentdir, 0755); err != nil {
+		t.Fatalf("creating imagedb content dir %v", err)
+	}
+	configdata := []byte(`{"architecture": "amd64", "os": "linux", "rootfs": {"type": "layers", "diff_ids": ["sha256:aaaa"]}}`)
+	if err := ioutil.WriteFile(filepath.Join(contentdir, "cccc"), configdata, 0644); err != nil {
+		t.Fatalf("writing image content blob %v", err)
+	}
+
+	diffiddir := filepath.Join(storagedir, "distribution", "diffid-by-digest", "sha256")
+	if err := os.MkdirAll(diffiddir, 0755); err != nil {
+		t.Fatalf("creating diffid dir %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(diffiddir, "eeee"), []byte("sha256:ffff"), 0644); err != nil {
+		t.Fatalf("writing diffid file %v", err)
+	}
+
+	repositoriesdata := []byte(`{"Repositories": {"myrepo": {"myrepo:latest": "sha256:cccc"}}}`)
+	if err := ioutil.WriteFile(filepath.Join(storagedir, repositoriesFileName), repositoriesdata, 0644); err != nil {
+		t.Fatalf("writing repositories.json %v", err)
+	}
+
+	e := &explorer{root: root}
+	contents, err := e.ListContent(context.Background())
+	if err != nil {
+		t.Fatalf("ListContent: unexpected error %v", err)
+	}
+
+	byDigest := map[string]explorers.Content{}
+	for _, c := range contents {
+		byDigest[c.Digest] = c
+	}
+
+	configcontent, ok := byDigest["sha256:cccc"]
+	if !ok {
+		t.Fatalf("contents = %+v, want an entry for sha256:cccc", contents)
+	}
+	if len(configcontent.Tags) != 1 || configcontent.Tags[0] != "myrepo:latest" {
+		t.Errorf("Tags = %+v, want [myrepo:latest]", configcontent.Tags)
+	}
+
+	if _, ok := byDigest["sha256:ffff"]; !ok {
+		t.Errorf("contents = %+v, want an entry for the diffid-by-digest layer sha256:ffff", contents)
+	}
+}
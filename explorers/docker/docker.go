@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -31,8 +30,11 @@ import (
 	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/metadata"
 	"github.com/google/container-explorer/explorers"
+	"github.com/google/container-explorer/explorers/graphdriver"
+	"github.com/google/container-explorer/manifest"
 	digest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	runtimespec "github.com/opencontainers/runtime-spec/specs-go"
 	log "github.com/sirupsen/logrus"
 	bolt "go.etcd.io/bbolt"
 )
@@ -41,10 +43,10 @@ const (
 	configV1Filename     = "config.json"
 	configV2Filename     = "config.v2.json"
 	containersDirName    = "containers"
-	lowerdirName         = "lower"
 	repositoriesDirName  = "image"
 	repositoriesFileName = "repositories.json"
 	storageOverlay2      = "overlay2"
+	runtimeSpecFilename  = "config.json"
 )
 
 type ImageName map[string]string
@@ -59,11 +61,15 @@ type explorer struct {
 	manifest      string
 	snapshot      string
 	mdb           *bolt.DB // manifest database file
+	// platform ("os/arch", e.g. "linux/amd64") is the platform to resolve
+	// multi-arch manifest lists against. Empty falls back to the first
+	// listed manifest.
+	platform string
 }
 
 // NewExplorer returns a ContainerExplorer interface to explorer docker managed
 // containers.
-func NewExplorer(root string, containerdroot string, manifest string, snapshot string) (explorers.ContainerExplorer, error) {
+func NewExplorer(root string, containerdroot string, manifest string, snapshot string, platform string) (explorers.ContainerExplorer, error) {
 	opt := &bolt.Options{
 		ReadOnly: true,
 	}
@@ -78,6 +84,7 @@ func NewExplorer(root string, containerdroot string, manifest string, snapshot s
 		manifest:      manifest,
 		snapshot:      snapshot,
 		mdb:           db,
+		platform:      platform,
 	}, nil
 }
 
@@ -212,8 +219,6 @@ type imageContentSummary struct {
 
 // ListImages returns information about docker images.
 func (e *explorer) ListImages(ctx context.Context) ([]explorers.Image, error) {
-	// TODO (rmaskey): Handle docker version 1 images
-
 	// Docker version 2
 	//
 	// Check for valid image repositories directory
@@ -258,29 +263,244 @@ func (e *explorer) ListImages(ctx context.Context) ([]explorers.Image, error) {
 					},
 				}
 
+				var history []explorers.HistoryEntry
+				var layers []explorers.LayerInfo
+
 				if storagename == storageOverlay2 {
-					imagecontent, err := readImageContent(storagename, storagedir, image.Target.Digest)
+					imagecontent, layerdescs, err := readImageContent(storagename, storagedir, image.Target.Digest, e.platform)
 					if err != nil {
 						log.Error("reading image content file ", err)
 					} else {
 						image.CreatedAt = imagecontent.Created
+						history, layers = buildHistoryAndLayers(storagedir, imagecontent, layerdescs)
 					}
 				}
 
 				ceimages = append(ceimages, explorers.Image{
-					Image: image,
+					History: history,
+					Layers:  layers,
+					Image:   image,
 				})
 			}
 		}
 	}
 
+	if err := appendV1Images(e.root, &ceimages); err != nil {
+		log.Debug("listing docker v1 images ", err)
+	}
+
 	return ceimages, nil
 }
 
-// ListContent returns content information.
+// appendV1Images adds pre-content-addressable-store docker v1 images to
+// imgs, parsing root/graph/<id>/json the way docker itself did before the
+// image/<driver> layout was introduced.
+func appendV1Images(root string, imgs *[]explorers.Image) error {
+	graphdir := filepath.Join(root, "graph")
+	if !fileExists(graphdir) {
+		return nil
+	}
+
+	entries, err := filepath.Glob(filepath.Join(graphdir, "*"))
+	if err != nil {
+		return fmt.Errorf("listing graph directory %v", err)
+	}
+
+	tags := v1Tags(root)
+
+	for _, entry := range entries {
+		_, imageid := filepath.Split(entry)
+
+		jsonpath := filepath.Join(entry, "json")
+		if !fileExists(jsonpath) {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(jsonpath)
+		if err != nil {
+			log.WithField("file", jsonpath).Debug("reading docker v1 image json ", err)
+			continue
+		}
+
+		var summary imageContentSummary
+		if err := json.Unmarshal(data, &summary); err != nil {
+			log.WithField("file", jsonpath).Debug("unmarshalling docker v1 image json ", err)
+			continue
+		}
+
+		name := imageid
+		if names, ok := tags[imageid]; ok && len(names) > 0 {
+			name = names[0]
+		}
+
+		*imgs = append(*imgs, explorers.Image{
+			Image: images.Image{
+				Name:      name,
+				Target:    ocispec.Descriptor{Digest: digest.Digest("sha256:" + imageid)},
+				CreatedAt: summary.Created,
+			},
+		})
+	}
+
+	return nil
+}
+
+// v1Tags reads every legacy root/repositories-<driver> file docker v1 used
+// before image repositories moved under image/<driver>, and returns the
+// tag names pointing at each v1 image id.
+func v1Tags(root string) map[string][]string {
+	tags := map[string][]string{}
+
+	files, err := filepath.Glob(filepath.Join(root, "repositories-*"))
+	if err != nil {
+		return tags
+	}
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		var r ImageRepository
+		if err := json.Unmarshal(data, &r); err != nil {
+			continue
+		}
+
+		for _, names := range r.Repositories {
+			for name, imageid := range names {
+				tags[imageid] = append(tags[imageid], name)
+			}
+		}
+	}
+
+	return tags
+}
+
+// ListContent returns content information by walking each storage driver's
+// imagedb content store and distribution diffid index.
 func (e *explorer) ListContent(ctx context.Context) ([]explorers.Content, error) {
-	// TODO(rmaskey): implement the function
-	return nil, nil
+	repositoriesdir := filepath.Join(e.root, repositoriesDirName)
+	if !fileExists(repositoriesdir) {
+		return nil, fmt.Errorf("valid image repositories directory %s not found", repositoriesdir)
+	}
+
+	storagedirs, err := filepath.Glob(filepath.Join(repositoriesdir, "*"))
+	if err != nil {
+		return nil, fmt.Errorf("listing storage directories %v", err)
+	}
+
+	tagsByDigest, err := tagsByDigest(storagedirs)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []explorers.Content
+
+	for _, storagedir := range storagedirs {
+		contentdir := filepath.Join(storagedir, "imagedb", "content", "sha256")
+		if fileExists(contentdir) {
+			blobs, err := filepath.Glob(filepath.Join(contentdir, "*"))
+			if err != nil {
+				return nil, fmt.Errorf("listing image content directory %s %v", contentdir, err)
+			}
+
+			for _, blob := range blobs {
+				_, filename := filepath.Split(blob)
+				d := digest.NewDigestFromEncoded(digest.SHA256, filename)
+
+				info, err := os.Stat(blob)
+				if err != nil {
+					log.WithField("file", blob).Debug("stat image content file ", err)
+					continue
+				}
+
+				data, err := ioutil.ReadFile(blob)
+				if err != nil {
+					log.WithField("file", blob).Debug("reading image content file ", err)
+					continue
+				}
+
+				contents = append(contents, explorers.Content{
+					Digest:    d.String(),
+					Size:      info.Size(),
+					MediaType: contentMediaType(data),
+					Tags:      tagsByDigest[d.String()],
+				})
+			}
+		}
+
+		diffiddir := filepath.Join(storagedir, "distribution", "diffid-by-digest", "sha256")
+		if fileExists(diffiddir) {
+			diffids, err := filepath.Glob(filepath.Join(diffiddir, "*"))
+			if err != nil {
+				return nil, fmt.Errorf("listing distribution diffid directory %s %v", diffiddir, err)
+			}
+
+			for _, diffidfile := range diffids {
+				data, err := ioutil.ReadFile(diffidfile)
+				if err != nil {
+					log.WithField("file", diffidfile).Debug("reading distribution diffid file ", err)
+					continue
+				}
+
+				layerdigest := strings.TrimSpace(string(data))
+				contents = append(contents, explorers.Content{
+					Digest:    layerdigest,
+					MediaType: "application/vnd.docker.image.rootfs.diff.tar.gzip",
+					Tags:      tagsByDigest[layerdigest],
+				})
+			}
+		}
+	}
+
+	return contents, nil
+}
+
+// contentMediaType infers the media type of an imagedb content blob from its
+// JSON payload: a docker image config document, or one of the manifest
+// shapes package manifest parses. Anything else is reported as
+// application/octet-stream.
+func contentMediaType(data []byte) string {
+	var summary imageContentSummary
+	if err := json.Unmarshal(data, &summary); err == nil && summary.Rootfs.Rfstype != "" {
+		return "application/vnd.docker.container.image.v1+json"
+	}
+	if m, err := manifest.Parse(data); err == nil && m.MediaType() != "" {
+		return m.MediaType()
+	}
+	return "application/octet-stream"
+}
+
+// tagsByDigest returns the set of "repo:tag" names that point at each image
+// digest, across every storage driver directory in storagedirs.
+func tagsByDigest(storagedirs []string) (map[string][]string, error) {
+	tags := map[string][]string{}
+
+	for _, storagedir := range storagedirs {
+		repositoriesfile := filepath.Join(storagedir, repositoriesFileName)
+		if !fileExists(repositoriesfile) {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(repositoriesfile)
+		if err != nil {
+			return nil, fmt.Errorf("reading repository file %s %v", repositoriesfile, err)
+		}
+
+		var r ImageRepository
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("unmarshalling image repository file %s %v", repositoriesfile, err)
+		}
+
+		for _, names := range r.Repositories {
+			for name, dgst := range names {
+				tags[dgst] = append(tags[dgst], name)
+			}
+		}
+	}
+
+	return tags, nil
 }
 
 // ListSnapshots returns snapshot information.
@@ -289,73 +509,118 @@ func (e *explorer) ListSnapshots(ctx context.Context) ([]explorers.SnapshotKeyIn
 	return nil, nil
 }
 
-// InfoContainer returns container internal information.
+// InfoContainer returns container internal information equivalent to
+// `docker inspect` / `podman inspect`, combining the on-disk container
+// configuration, the resolved graph-driver mount points and, when spec is
+// true, the OCI runtime spec used to create the container.
 func (e *explorer) InfoContainer(ctx context.Context, containerid string, spec bool) (interface{}, error) {
-	// default return
-	return nil, nil
-}
-
-// MountContainer mounts a container to the specified path
-func (e *explorer) MountContainer(ctx context.Context, containerid string, mountpoint string) error {
 	container, err := e.getContainer(ctx, containerid)
 	if err != nil {
-		return fmt.Errorf("getting container %v", err)
+		return nil, fmt.Errorf("getting container %v", err)
 	}
 
-	containerMountIDPath := filepath.Join(e.root, repositoriesDirName, container.Driver, "layerdb", "mounts", containerid, "mount-id")
-	log.WithField("containerMountIDPath", containerMountIDPath).Debug("container mount-id path")
+	info := &explorers.ContainerInfo{
+		ID:              container.ID,
+		Created:         container.Created,
+		Path:            container.Path,
+		Args:            container.Args,
+		Image:           container.Image,
+		ResolvConfPath:  container.ResolvConfPath,
+		HostnamePath:    container.HostnamePath,
+		HostsPath:       container.HostsPath,
+		LogPath:         container.LogPath,
+		Name:            container.Name,
+		RestartCount:    container.RestartCount,
+		Driver:          container.Driver,
+		Platform:        container.Platform,
+		MountLabel:      container.MountLabel,
+		ProcessLabel:    container.ProcessLabel,
+		AppArmorProfile: container.AppArmorProfile,
+		State: explorers.ContainerState{
+			Running:    container.State.Running,
+			Paused:     container.State.Paused,
+			Restarting: container.State.Restarting,
+			OOMKilled:  container.State.OOMKilled,
+			Dead:       container.State.Dead,
+			Pid:        container.State.Pid,
+			ExitCode:   container.State.ExitCode,
+			Error:      container.State.Error,
+			StartedAt:  container.State.StartedAt,
+			FinishedAt: container.State.FinishedAt,
+		},
+	}
 
-	mountIDByte, err := ioutil.ReadFile(containerMountIDPath)
-	if err != nil {
-		return fmt.Errorf("reading container mount-id")
+	if container.NetworkSettings != nil {
+		info.NetworkSettings = container.NetworkSettings
 	}
-	mountID := string(mountIDByte)
-	log.WithField("mount-id", mountID).Debug("container mount-id")
 
-	// build container lower directory
-	lowerdirpath := filepath.Join(e.root, container.Driver, mountID, lowerdirName)
-	log.WithField("lowerdirpath", lowerdirpath).Debug("container lowerdir path")
-	data, err := ioutil.ReadFile(lowerdirpath)
+	graphdriver, err := e.graphDriverData(container)
 	if err != nil {
-		return fmt.Errorf("reading lower file %v", err)
+		log.WithField("containerid", containerid).Debug("resolving graph driver data ", err)
+	} else {
+		info.GraphDriver = graphdriver
 	}
 
-	var lowerdir string
-	for i, ldir := range strings.Split(string(data), ":") {
-		ldirpath := filepath.Join(e.root, container.Driver, ldir)
-		if i == 0 {
-			lowerdir = ldirpath
-			continue
+	if spec {
+		runtimeSpecPath := filepath.Join(e.root, containersDirName, containerid, runtimeSpecFilename)
+		if fileExists(runtimeSpecPath) {
+			data, err := ioutil.ReadFile(runtimeSpecPath)
+			if err != nil {
+				return nil, fmt.Errorf("reading OCI runtime spec %s %v", runtimeSpecPath, err)
+			}
+
+			var runtimeConfig runtimespec.Spec
+			if err := json.Unmarshal(data, &runtimeConfig); err != nil {
+				return nil, fmt.Errorf("unmarshalling OCI runtime spec %v", err)
+			}
+			info.Spec = &runtimeConfig
+		} else {
+			log.WithField("runtimeSpecPath", runtimeSpecPath).Debug("OCI runtime spec not found")
 		}
-		lowerdir = fmt.Sprintf("%s:%s", lowerdir, ldirpath)
 	}
 
-	upperdir := filepath.Join(e.root, container.Driver, mountID, "diff")
-	workdir := filepath.Join(e.root, container.Driver, mountID, "work")
+	return info, nil
+}
 
-	log.WithFields(log.Fields{
-		"lowerdir": lowerdir,
-		"upperdir": upperdir,
-		"workdir":  workdir,
-	}).Debug("container overlay directories")
+// graphDriverData resolves the graph-driver-specific mount points for
+// container, delegating to the registered graphdriver.Driver for
+// container.Driver so every supported backend (overlay2, aufs, btrfs,
+// devicemapper, legacy overlay, zfs) reports its own Data keys rather
+// than assuming overlay2's LowerDir/UpperDir/WorkDir/MergedDir shape.
+func (e *explorer) graphDriverData(container ConfigFile) (explorers.GraphDriverData, error) {
+	driver, err := graphdriver.Get(container.Driver)
+	if err != nil {
+		return explorers.GraphDriverData{}, err
+	}
 
-	// mounting container
-	mountopts := fmt.Sprintf("ro,lowerdir=%s:%s", lowerdir, upperdir)
-	mountargs := []string{"-t", "overlay", "overlay", "-o", mountopts, mountpoint}
+	data, err := driver.Data(e.root, container.ID)
+	if err != nil {
+		return explorers.GraphDriverData{}, err
+	}
+
+	return explorers.GraphDriverData{
+		Name: container.Driver,
+		Data: data,
+	}, nil
+}
 
-	cmd := exec.Command("mount", mountargs...)
-	out, err := cmd.CombinedOutput()
+// MountContainer mounts a container to the specified path. The graph
+// driver to use is looked up from the container's "Driver" field, so
+// images captured on hosts that weren't configured with overlay2 (aufs,
+// btrfs, devicemapper, legacy overlay, ...) are mounted correctly too.
+func (e *explorer) MountContainer(ctx context.Context, containerid string, mountpoint string) error {
+	container, err := e.getContainer(ctx, containerid)
 	if err != nil {
-		log.Errorf("running mount command %v", mountargs)
+		return fmt.Errorf("getting container %v", err)
+	}
 
-		if strings.Contains(err.Error(), " 32") {
-			return fmt.Errorf("invalid lowerdir path %v. Use --debug to view lowerdir path", err)
-		}
-		return fmt.Errorf("executing mount command %v", err)
+	driver, err := graphdriver.Get(container.Driver)
+	if err != nil {
+		return fmt.Errorf("looking up graph driver %v", err)
 	}
 
-	if string(out) != "" {
-		log.WithField("mount command", string(out)).Debug("container mount command")
+	if err := driver.Mount(e.root, containerid, mountpoint, true); err != nil {
+		return fmt.Errorf("mounting container %v", err)
 	}
 
 	return nil
@@ -431,11 +696,16 @@ func convertToContainerExplorerContainer(config ConfigFile) explorers.Container
 	}
 }
 
-// readImageContent reads the content of overlay2 image content
-func readImageContent(storagename string, storagepath string, digest digest.Digest) (imageContentSummary, error) {
+// readImageContent reads the content of an image's content-addressable
+// blob, tolerating both the plain docker image config documents found
+// under a dockerd-managed overlay2 store and the Docker Schema 1/2 and OCI
+// manifests (including manifest lists/indexes) found under stores
+// populated by skopeo or podman. platform ("os/arch") selects which
+// manifest to resolve when the content is a multi-arch manifest list.
+func readImageContent(storagename string, storagepath string, digest digest.Digest, platform string) (imageContentSummary, []ocispec.Descriptor, error) {
 	m := strings.Split(string(digest), ":")
 	if len(m) != 2 {
-		return imageContentSummary{}, fmt.Errorf("expecting two colon separated values")
+		return imageContentSummary{}, nil, fmt.Errorf("expecting two colon separated values")
 	}
 	algo := m[0]
 	filename := m[1]
@@ -453,13 +723,8 @@ func readImageContent(storagename string, storagepath string, digest digest.Dige
 			"filename":     filename,
 		}).Debug("reading docker image content file")
 
-		return imageContentSummary{}, err
-	}
-
-	var imagecontent imageContentSummary
-	if err := json.Unmarshal(data, &imagecontent); err != nil {
-		return imageContentSummary{}, err
+		return imageContentSummary{}, nil, err
 	}
 
-	return imagecontent, nil
+	return decodeImageContent(storagepath, data, platform)
 }
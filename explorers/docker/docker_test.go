@@ -0,0 +1,85 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/container-explorer/explorers"
+	runtimespec "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// TestInfoContainer covers InfoContainer's three graceful-degradation
+// paths: a missing OCI runtime spec when spec=true, an unsupported graph
+// driver, and a container with no recorded NetworkSettings.
+func TestInfoContainer(t *testing.T) {
+	root, err := ioutil.TempDir("", "docker-test-")
+	if err != nil {
+		t.Fatalf("creating temp dir %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	containerid := "abc123"
+	containerdir := filepath.Join(root, containersDirName, containerid)
+	if err := os.MkdirAll(containerdir, 0755); err != nil {
+		t.Fatalf("creating container dir %v", err)
+	}
+
+	configdata := []byte(`{"ID": "abc123", "Driver": "unsupported-driver", "Name": "/test"}`)
+	if err := ioutil.WriteFile(filepath.Join(containerdir, configV2Filename), configdata, 0644); err != nil {
+		t.Fatalf("writing container config %v", err)
+	}
+
+	e := &explorer{root: root}
+
+	info, err := e.InfoContainer(context.Background(), containerid, true)
+	if err != nil {
+		t.Fatalf("InfoContainer: unexpected error with missing runtime spec %v", err)
+	}
+	ci, ok := info.(*explorers.ContainerInfo)
+	if !ok {
+		t.Fatalf("InfoContainer returned %T, want *explorers.ContainerInfo", info)
+	}
+	if ci.Spec != nil {
+		t.Errorf("Spec = %+v, want nil when config.json does not exist", ci.Spec)
+	}
+	if ci.GraphDriver.Name != "" || ci.GraphDriver.Data != nil {
+		t.Errorf("GraphDriver = %+v, want zero value for an unsupported driver", ci.GraphDriver)
+	}
+	if ci.NetworkSettings != nil {
+		t.Errorf("NetworkSettings = %+v, want nil when not present in config.v2.json", ci.NetworkSettings)
+	}
+
+	runtimeSpecPath := filepath.Join(containerdir, runtimeSpecFilename)
+	if err := ioutil.WriteFile(runtimeSpecPath, []byte(`{"ociVersion": "1.0.0"}`), 0644); err != nil {
+		t.Fatalf("writing runtime spec %v", err)
+	}
+
+	info, err = e.InfoContainer(context.Background(), containerid, true)
+	if err != nil {
+		t.Fatalf("InfoContainer: unexpected error with runtime spec present %v", err)
+	}
+	ci = info.(*explorers.ContainerInfo)
+	spec, ok := ci.Spec.(*runtimespec.Spec)
+	if !ok || spec.Version != "1.0.0" {
+		t.Errorf("Spec = %+v, want ociVersion 1.0.0 parsed from config.json", ci.Spec)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/container-explorer/explorers"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	log "github.com/sirupsen/logrus"
+)
+
+// buildHistoryAndLayers zips summary's non-empty history entries with its
+// rootfs diff ids, in order, computes each entry's layer chain ID and
+// resolves it down to its on-disk layerdb cache directory under
+// storagedir. layerdescs is the manifest's layer descriptor list, in the
+// same oldest-first order as summary.Rootfs.DiffIds, and is nil when the
+// image content blob was a plain config document rather than a manifest;
+// it supplies each resolved layer's compressed size and media type.
+func buildHistoryAndLayers(storagedir string, summary imageContentSummary, layerdescs []ocispec.Descriptor) ([]explorers.HistoryEntry, []explorers.LayerInfo) {
+	var history []explorers.HistoryEntry
+	var layers []explorers.LayerInfo
+
+	var parentChainID string
+	diffidx := 0
+
+	for _, h := range summary.History {
+		entry := explorers.HistoryEntry{
+			Created:    h.Created,
+			CreatedBy:  h.CreatedBy,
+			Comment:    h.Comment,
+			EmptyLayer: h.EmptyLayer,
+		}
+
+		if !h.EmptyLayer && diffidx < len(summary.Rootfs.DiffIds) {
+			diffID := summary.Rootfs.DiffIds[diffidx]
+			diffidx++
+
+			cid := chainID(parentChainID, diffID)
+			entry.LayerDigest = diffID
+
+			layer := explorers.LayerInfo{
+				DiffID:        diffID,
+				ChainID:       cid,
+				ParentChainID: parentChainID,
+			}
+
+			if desc := layerDescriptor(layerdescs, diffidx-1); desc != nil {
+				layer.CompressedSize = desc.Size
+				layer.MediaType = desc.MediaType
+			}
+
+			if cachedir, err := layerCacheDir(storagedir, cid); err != nil {
+				log.WithField("chainid", cid).Debug("resolving layer cache directory ", err)
+			} else {
+				if cacheid, err := ioutil.ReadFile(filepath.Join(cachedir, "cache-id")); err == nil {
+					layer.CacheID = strings.TrimSpace(string(cacheid))
+				}
+				if size, err := readLayerSize(filepath.Join(cachedir, "size")); err == nil {
+					layer.Size = size
+					entry.Size = size
+				}
+			}
+
+			layers = append(layers, layer)
+			parentChainID = cid
+		}
+
+		history = append(history, entry)
+	}
+
+	return history, layers
+}
+
+// layerDescriptor returns the idx'th entry of layerdescs, or nil if idx is
+// out of range, e.g. because the image content blob was a plain config
+// document with no accompanying manifest.
+func layerDescriptor(layerdescs []ocispec.Descriptor, idx int) *ocispec.Descriptor {
+	if idx < 0 || idx >= len(layerdescs) {
+		return nil
+	}
+	return &layerdescs[idx]
+}
+
+// chainID computes a layer chain ID the way docker's layer store does:
+// chainID(0)=diffID(0); chainID(n)=sha256(chainID(n-1)+" "+diffID(n)).
+func chainID(parentChainID, diffID string) string {
+	if parentChainID == "" {
+		return diffID
+	}
+	sum := sha256.Sum256([]byte(parentChainID + " " + diffID))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// layerCacheDir resolves a chain ID to its on-disk layerdb cache
+// directory, i.e. image/<driver>/layerdb/sha256/<chainid>.
+func layerCacheDir(storagedir, chainID string) (string, error) {
+	m := strings.Split(chainID, ":")
+	if len(m) != 2 {
+		return "", fmt.Errorf("expecting two colon separated values in chain id %s", chainID)
+	}
+	return filepath.Join(storagedir, "layerdb", m[0], m[1]), nil
+}
+
+// readLayerSize parses a layerdb cache directory's "size" file.
+func readLayerSize(path string) (int64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
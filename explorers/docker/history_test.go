@@ -0,0 +1,56 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestChainIDBase(t *testing.T) {
+	if got := chainID("", "sha256:aaaa"); got != "sha256:aaaa" {
+		t.Errorf("chainID(\"\", aaaa) = %q, want sha256:aaaa", got)
+	}
+}
+
+func TestChainIDRecurrence(t *testing.T) {
+	want := "sha256:2c4d8760379f05d0ad1ed610712309f0114e7345e6e6a351494cff030ffdf197"
+	if got := chainID("sha256:aaaa", "sha256:bbbb"); got != want {
+		t.Errorf("chainID(aaaa, bbbb) = %q, want %q", got, want)
+	}
+}
+
+func TestLayerDescriptor(t *testing.T) {
+	descs := []ocispec.Descriptor{
+		{Digest: "sha256:aaaa", Size: 100},
+		{Digest: "sha256:bbbb", Size: 200},
+	}
+
+	if got := layerDescriptor(descs, 1); got == nil || got.Size != 200 {
+		t.Errorf("layerDescriptor(descs, 1) = %+v, want size 200", got)
+	}
+	if got := layerDescriptor(descs, 2); got != nil {
+		t.Errorf("layerDescriptor(descs, 2) = %+v, want nil for an out-of-range index", got)
+	}
+	if got := layerDescriptor(descs, -1); got != nil {
+		t.Errorf("layerDescriptor(descs, -1) = %+v, want nil for a negative index", got)
+	}
+	if got := layerDescriptor(nil, 0); got != nil {
+		t.Errorf("layerDescriptor(nil, 0) = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/container-explorer/manifest"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// decodeImageContent parses data, the content of an imagedb content blob,
+// as either a plain docker image config document (the original, and still
+// most common, on-disk shape) or a manifest (Docker Schema 1/2 or OCI,
+// including manifest lists/indexes, the shape skopeo and podman tend to
+// leave behind) and returns the resolved image config, plus the manifest's
+// layer descriptors when data was a manifest rather than a plain config
+// document. platform ("os/arch", e.g. "linux/amd64") selects the manifest
+// to resolve from a multi-arch manifest list; an empty platform falls back
+// to the first listed manifest.
+func decodeImageContent(storagepath string, data []byte, platform string) (imageContentSummary, []ocispec.Descriptor, error) {
+	m, err := manifest.Parse(data)
+	if err != nil {
+		var summary imageContentSummary
+		if err := json.Unmarshal(data, &summary); err != nil {
+			return imageContentSummary{}, nil, err
+		}
+		return summary, nil, nil
+	}
+
+	if list, ok := m.(manifest.List); ok {
+		m, err = resolvePlatformManifest(storagepath, list, platform)
+		if err != nil {
+			return imageContentSummary{}, nil, fmt.Errorf("resolving platform manifest %v", err)
+		}
+	}
+
+	if v1compat, ok := manifest.V1Compatibility(m); ok {
+		var summary imageContentSummary
+		if err := json.Unmarshal(v1compat, &summary); err != nil {
+			return imageContentSummary{}, nil, fmt.Errorf("unmarshalling schema1 v1Compatibility %v", err)
+		}
+		return summary, m.Layers(), nil
+	}
+
+	configdata, err := readContentBlob(storagepath, m.Config().Digest)
+	if err != nil {
+		return imageContentSummary{}, nil, fmt.Errorf("reading manifest config blob %v", err)
+	}
+
+	var summary imageContentSummary
+	if err := json.Unmarshal(configdata, &summary); err != nil {
+		return imageContentSummary{}, nil, fmt.Errorf("unmarshalling manifest config blob %v", err)
+	}
+	return summary, m.Layers(), nil
+}
+
+// resolvePlatformManifest descends into list and parses the manifest
+// matching platform ("os/arch"). An empty platform falls back to the first
+// listed entry; a non-empty platform that matches no entry is an error,
+// since the evidence being examined was not necessarily captured on a host
+// matching the analysis machine's own platform.
+func resolvePlatformManifest(storagepath string, list manifest.List, platform string) (manifest.Manifest, error) {
+	descs := list.Manifests()
+	if len(descs) == 0 {
+		return nil, fmt.Errorf("manifest list has no manifests")
+	}
+
+	chosen := descs[0]
+	if platform != "" {
+		os, arch, err := splitPlatform(platform)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, d := range descs {
+			if d.Platform != nil && d.Platform.OS == os && d.Platform.Architecture == arch {
+				chosen = d
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no manifest for platform %s", platform)
+		}
+	}
+
+	data, err := readContentBlob(storagepath, chosen.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("reading platform manifest blob %v", err)
+	}
+
+	return manifest.Parse(data)
+}
+
+// splitPlatform splits platform ("os/arch", e.g. "linux/amd64") into its
+// os and arch components.
+func splitPlatform(platform string) (os string, arch string, err error) {
+	parts := strings.SplitN(platform, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid platform %q, expecting \"os/arch\"", platform)
+	}
+	return parts[0], parts[1], nil
+}
+
+// readContentBlob reads a content-addressable blob by digest from
+// storagepath's imagedb content store.
+func readContentBlob(storagepath string, d digest.Digest) ([]byte, error) {
+	m := strings.Split(string(d), ":")
+	if len(m) != 2 {
+		return nil, fmt.Errorf("expecting two colon separated values in digest %s", d)
+	}
+	return ioutil.ReadFile(filepath.Join(storagepath, "imagedb", "content", m[0], m[1]))
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeImageContentPlainConfig(t *testing.T) {
+	data := []byte(`{"architecture": "amd64", "os": "linux", "rootfs": {"type": "layers", "diff_ids": ["sha256:aaaa"]}}`)
+
+	summary, layerdescs, err := decodeImageContent("", data, "")
+	if err != nil {
+		t.Fatalf("decodeImageContent: unexpected error %v", err)
+	}
+	if summary.Architecture != "amd64" {
+		t.Errorf("Architecture = %q, want amd64", summary.Architecture)
+	}
+	if layerdescs != nil {
+		t.Errorf("layerdescs = %+v, want nil for a plain config document", layerdescs)
+	}
+}
+
+func TestDecodeImageContentManifest(t *testing.T) {
+	storagedir, err := ioutil.TempDir("", "manifest-test-")
+	if err != nil {
+		t.Fatalf("creating temp dir %v", err)
+	}
+	defer os.RemoveAll(storagedir)
+
+	configdata := []byte(`{"architecture": "amd64", "os": "linux", "rootfs": {"type": "layers", "diff_ids": ["sha256:aaaa"]}}`)
+	configdir := filepath.Join(storagedir, "imagedb", "content", "sha256")
+	if err := os.MkdirAll(configdir, 0755); err != nil {
+		t.Fatalf("creating config blob dir %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(configdir, "cccc"), configdata, 0644); err != nil {
+		t.Fatalf("writing config blob %v", err)
+	}
+
+	manifestdata := []byte(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+		"config": {"mediaType": "application/vnd.docker.container.image.v1+json", "digest": "sha256:cccc", "size": 10},
+		"layers": [
+			{"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip", "digest": "sha256:aaaa", "size": 100}
+		]
+	}`)
+
+	summary, layerdescs, err := decodeImageContent(storagedir, manifestdata, "")
+	if err != nil {
+		t.Fatalf("decodeImageContent: unexpected error %v", err)
+	}
+	if summary.Architecture != "amd64" {
+		t.Errorf("Architecture = %q, want amd64", summary.Architecture)
+	}
+	if len(layerdescs) != 1 || layerdescs[0].Size != 100 {
+		t.Errorf("layerdescs = %+v, want single descriptor of size 100", layerdescs)
+	}
+}
+
+func TestDecodeImageContentSchema1(t *testing.T) {
+	manifestdata := []byte(`{
+		"schemaVersion": 1,
+		"architecture": "amd64",
+		"fsLayers": [
+			{"blobSum": "sha256:bbbb"},
+			{"blobSum": "sha256:aaaa"}
+		],
+		"history": [
+			{"v1Compatibility": "{\"architecture\": \"amd64\", \"os\": \"linux\"}"}
+		]
+	}`)
+
+	summary, layerdescs, err := decodeImageContent("", manifestdata, "")
+	if err != nil {
+		t.Fatalf("decodeImageContent: unexpected error %v", err)
+	}
+	if summary.Architecture != "amd64" {
+		t.Errorf("Architecture = %q, want amd64", summary.Architecture)
+	}
+	if len(layerdescs) != 2 {
+		t.Errorf("layerdescs = %+v, want 2 entries", layerdescs)
+	}
+}
+
+func TestDecodeImageContentManifestListSelectsPlatform(t *testing.T) {
+	storagedir, err := ioutil.TempDir("", "manifest-test-")
+	if err != nil {
+		t.Fatalf("creating temp dir %v", err)
+	}
+	defer os.RemoveAll(storagedir)
+
+	configdata := []byte(`{"architecture": "arm64", "os": "linux", "rootfs": {"type": "layers", "diff_ids": ["sha256:aaaa"]}}`)
+	configdir := filepath.Join(storagedir, "imagedb", "content", "sha256")
+	if err := os.MkdirAll(configdir, 0755); err != nil {
+		t.Fatalf("creating config blob dir %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(configdir, "cccc"), configdata, 0644); err != nil {
+		t.Fatalf("writing config blob %v", err)
+	}
+
+	platformmanifest := []byte(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+		"config": {"mediaType": "application/vnd.docker.container.image.v1+json", "digest": "sha256:cccc", "size": 10},
+		"layers": []
+	}`)
+	if err := ioutil.WriteFile(filepath.Join(configdir, "dddd"), platformmanifest, 0644); err != nil {
+		t.Fatalf("writing platform manifest blob %v", err)
+	}
+
+	listdata := []byte(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+		"manifests": [
+			{"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "digest": "sha256:dddd", "size": 10, "platform": {"architecture": "arm64", "os": "linux"}}
+		]
+	}`)
+
+	if _, _, err := decodeImageContent(storagedir, listdata, "linux/amd64"); err == nil {
+		t.Errorf("decodeImageContent(platform=linux/amd64): expected error, no manifest matches that platform")
+	}
+
+	summary, _, err := decodeImageContent(storagedir, listdata, "linux/arm64")
+	if err != nil {
+		t.Fatalf("decodeImageContent(platform=linux/arm64): unexpected error %v", err)
+	}
+	if summary.Architecture != "arm64" {
+		t.Errorf("Architecture = %q, want arm64", summary.Architecture)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphdriver
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register(&aufsDriver{})
+}
+
+// aufsDriver mounts containers managed by docker's aufs graph driver.
+type aufsDriver struct{}
+
+func (aufsDriver) Name() string { return "aufs" }
+
+// Layers returns id's aufs layer chain, read from aufs/layers/<id>, ordered
+// child-to-parent, oldest last.
+func (aufsDriver) Layers(root, id string) ([]string, error) {
+	layersFile := filepath.Join(root, "aufs", "layers", id)
+	f, err := os.Open(layersFile)
+	if err != nil {
+		return nil, fmt.Errorf("opening aufs layers file %v", err)
+	}
+	defer f.Close()
+
+	var layers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		layerID := strings.TrimSpace(scanner.Text())
+		if layerID == "" {
+			continue
+		}
+		layers = append(layers, filepath.Join(root, "aufs", "diff", layerID))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading aufs layers file %v", err)
+	}
+
+	return layers, nil
+}
+
+func (d aufsDriver) Mount(root, id, mountpoint string, readonly bool) error {
+	parents, err := d.Layers(root, id)
+	if err != nil {
+		return err
+	}
+
+	// The container's own writable diff dir goes first in the aufs
+	// branch chain (rw:... for moby, but container-explorer always
+	// mounts ro so the writable layer is just another read-only branch).
+	branches := append([]string{filepath.Join(root, "aufs", "diff", id)}, parents...)
+
+	var brArgs []string
+	for _, b := range branches {
+		brArgs = append(brArgs, fmt.Sprintf("%s=ro", b))
+	}
+
+	mountopts := fmt.Sprintf("br:%s", strings.Join(brArgs, ":"))
+	mountargs := []string{"-t", "aufs", "-o", mountopts, "none", mountpoint}
+
+	cmd := exec.Command("mount", mountargs...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("executing aufs mount command %v. %s", err, out)
+	}
+
+	return nil
+}
+
+// UpperDir returns the container's own aufs diff directory, its writable
+// layer, keyed by the raw container id rather than a resolved mount-id.
+func (aufsDriver) UpperDir(root, id string) (string, error) {
+	return filepath.Join(root, "aufs", "diff", id), nil
+}
+
+// Data returns aufs's GraphDriver.Data block: its own root directory,
+// matching the key real docker's aufs driver reports.
+func (d aufsDriver) Data(root, id string) (map[string]string, error) {
+	upperdir, err := d.UpperDir(root, id)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"RootDir": upperdir}, nil
+}
+
+func (aufsDriver) Unmount(mountpoint string) error {
+	return unmount(mountpoint)
+}
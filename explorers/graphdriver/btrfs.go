@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphdriver
+
+import "path/filepath"
+
+func init() {
+	Register(&btrfsDriver{})
+}
+
+// btrfsDriver mounts containers managed by docker's btrfs graph driver.
+// Each container and image layer is its own subvolume, so there is no
+// layer chain to assemble: mounting is a plain read-only bind mount of the
+// container's subvolume.
+type btrfsDriver struct{}
+
+func (btrfsDriver) Name() string { return "btrfs" }
+
+func (btrfsDriver) Layers(root, id string) ([]string, error) {
+	return []string{filepath.Join(root, "btrfs", "subvolumes", id)}, nil
+}
+
+func (d btrfsDriver) Mount(root, id, mountpoint string, readonly bool) error {
+	layers, err := d.Layers(root, id)
+	if err != nil {
+		return err
+	}
+	return bindMountReadonly(layers[0], mountpoint)
+}
+
+// UpperDir returns the container's own subvolume: btrfs has no separate
+// writable directory, the subvolume itself is diffed against its parent.
+func (btrfsDriver) UpperDir(root, id string) (string, error) {
+	return filepath.Join(root, "btrfs", "subvolumes", id), nil
+}
+
+// Data returns btrfs's GraphDriver.Data block: the subvolume path
+// backing the container.
+func (d btrfsDriver) Data(root, id string) (map[string]string, error) {
+	upperdir, err := d.UpperDir(root, id)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"SubvolumePath": upperdir}, nil
+}
+
+func (btrfsDriver) Unmount(mountpoint string) error {
+	return unmount(mountpoint)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphdriver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+)
+
+func init() {
+	Register(&devicemapperDriver{})
+}
+
+// deviceMetadata mirrors the per-device metadata devicemapper writes to
+// devicemapper/metadata/<id>.
+type deviceMetadata struct {
+	DeviceID           int    `json:"device_id"`
+	Size               uint64 `json:"size"`
+	TransactionID      int64  `json:"transaction_id"`
+	DeviceName         string `json:"device_name"`
+}
+
+// devicemapperDriver mounts containers managed by docker's devicemapper
+// graph driver by activating the container's thin device from the pool's
+// metadata and mounting the resulting filesystem.
+type devicemapperDriver struct{}
+
+func (devicemapperDriver) Name() string { return "devicemapper" }
+
+func (devicemapperDriver) Layers(root, id string) ([]string, error) {
+	// devicemapper has no layer directories to enumerate: every layer is
+	// a thin device snapshot, not a filesystem path.
+	return nil, fmt.Errorf("devicemapper does not expose layer directories; mount the container to inspect its filesystem")
+}
+
+func (devicemapperDriver) readMetadata(root, id string) (deviceMetadata, error) {
+	metadataPath := filepath.Join(root, "devicemapper", "metadata", id)
+	data, err := ioutil.ReadFile(metadataPath)
+	if err != nil {
+		return deviceMetadata{}, fmt.Errorf("reading devicemapper metadata %v", err)
+	}
+
+	var md deviceMetadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return deviceMetadata{}, fmt.Errorf("unmarshalling devicemapper metadata %v", err)
+	}
+	return md, nil
+}
+
+func (d devicemapperDriver) Mount(root, id, mountpoint string, readonly bool) error {
+	md, err := d.readMetadata(root, id)
+	if err != nil {
+		return err
+	}
+
+	poolName := fmt.Sprintf("container-explorer-%s", id)
+	poolDevice := "/dev/mapper/docker-thinpool" // the daemon's pool; must already be active on the analysis host
+
+	table := fmt.Sprintf("0 %d thin %s %d", md.Size/512, poolDevice, md.DeviceID)
+	cmd := exec.Command("dmsetup", "create", poolName, "--table", table)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("activating devicemapper thin device %v. %s", err, out)
+	}
+
+	devicePath := filepath.Join("/dev/mapper", poolName)
+	mountargs := []string{"-o", "ro", devicePath, mountpoint}
+	cmd = exec.Command("mount", mountargs...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		_ = exec.Command("dmsetup", "remove", poolName).Run()
+		return fmt.Errorf("mounting devicemapper thin device %v. %s", err, out)
+	}
+
+	return nil
+}
+
+// UpperDir always errors: devicemapper's thin devices have no on-disk
+// directory to diff against without activating and mounting them first.
+func (devicemapperDriver) UpperDir(root, id string) (string, error) {
+	return "", fmt.Errorf("devicemapper does not expose a writable layer directory; mount the container to inspect its filesystem")
+}
+
+// Data returns devicemapper's GraphDriver.Data block: the thin device's
+// id, name and size, the same keys real docker's devicemapper driver
+// reports.
+func (d devicemapperDriver) Data(root, id string) (map[string]string, error) {
+	md, err := d.readMetadata(root, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"DeviceId":   fmt.Sprintf("%d", md.DeviceID),
+		"DeviceName": md.DeviceName,
+		"DeviceSize": fmt.Sprintf("%d", md.Size),
+	}, nil
+}
+
+func (devicemapperDriver) Unmount(mountpoint string) error {
+	return unmount(mountpoint)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package graphdriver provides a pluggable backend for mounting a
+// container's root filesystem offline, mirroring the family of storage
+// drivers moby's daemon registers and dispatches on via a container's
+// "Driver" field.
+package graphdriver
+
+import "fmt"
+
+// Driver mounts and unmounts the root filesystem of a container managed by
+// a specific docker graph driver.
+type Driver interface {
+	// Name returns the graph driver name as recorded in a container's
+	// config.v2.json "Driver" field, e.g. "overlay2" or "aufs".
+	Name() string
+
+	// Mount assembles the root filesystem of the container identified by
+	// id, found under root, and mounts it at mountpoint. When readonly is
+	// true the container's writable layer is not mounted for writing.
+	Mount(root, id, mountpoint string, readonly bool) error
+
+	// Unmount undoes a previous Mount of mountpoint.
+	Unmount(mountpoint string) error
+
+	// Layers returns the ordered list of layer directories, topmost first,
+	// backing the container identified by id.
+	Layers(root, id string) ([]string, error)
+
+	// UpperDir returns the container's writable layer directory, the one
+	// DiffContainer walks to compute changes against the image. Drivers
+	// whose on-disk layout has no directory to diff against (e.g.
+	// devicemapper's thin devices) return an error.
+	UpperDir(root, id string) (string, error)
+
+	// Data returns the driver-specific key/value pairs docker inspect's
+	// GraphDriver.Data block reports for the container identified by id,
+	// e.g. overlay2's LowerDir/UpperDir/WorkDir/MergedDir or
+	// devicemapper's DeviceId/DeviceName/DeviceSize.
+	Data(root, id string) (map[string]string, error)
+}
+
+var drivers = map[string]Driver{}
+
+// Register makes a Driver available by name via Get. Graph driver
+// implementations call this from an init function.
+func Register(d Driver) {
+	drivers[d.Name()] = d
+}
+
+// Get returns the registered Driver for name, e.g. the value of a
+// container's "Driver" field.
+func Get(name string) (Driver, error) {
+	d, ok := drivers[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported graph driver %q", name)
+	}
+	return d, nil
+}
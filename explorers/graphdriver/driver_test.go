@@ -0,0 +1,38 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphdriver
+
+import "testing"
+
+func TestGetRegisteredDrivers(t *testing.T) {
+	for _, name := range []string{"overlay2", "overlay", "aufs", "btrfs", "devicemapper", "zfs"} {
+		d, err := Get(name)
+		if err != nil {
+			t.Errorf("Get(%q) returned error %v", name, err)
+			continue
+		}
+		if d.Name() != name {
+			t.Errorf("Get(%q).Name() = %q, want %q", name, d.Name(), name)
+		}
+	}
+}
+
+func TestGetUnsupportedDriver(t *testing.T) {
+	if _, err := Get("zzzfs"); err == nil {
+		t.Error("Get(\"zzzfs\") returned nil error, want an error for an unregistered driver")
+	}
+}
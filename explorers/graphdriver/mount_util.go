@@ -0,0 +1,75 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphdriver
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// mountOverlay runs `mount -t overlay` with the given comma separated
+// options against mountpoint.
+func mountOverlay(mountopts, mountpoint string) error {
+	mountargs := []string{"-t", "overlay", "overlay", "-o", mountopts, mountpoint}
+
+	cmd := exec.Command("mount", mountargs...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		log.Errorf("running mount command %v", mountargs)
+
+		if strings.Contains(err.Error(), " 32") {
+			return fmt.Errorf("invalid lowerdir path %v. Use --debug to view lowerdir path", err)
+		}
+		return fmt.Errorf("executing mount command %v", err)
+	}
+
+	if string(out) != "" {
+		log.WithField("mount command", string(out)).Debug("container mount command")
+	}
+
+	return nil
+}
+
+// bindMountReadonly bind mounts src at mountpoint read-only, the simplest
+// safe way to expose a single, already-flat layer directory for analysis.
+func bindMountReadonly(src, mountpoint string) error {
+	cmd := exec.Command("mount", "--bind", src, mountpoint)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("executing bind mount command %v. %s", err, out)
+	}
+
+	cmd = exec.Command("mount", "-o", "remount,ro,bind", mountpoint)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("remounting bind mount read-only %v. %s", err, out)
+	}
+
+	return nil
+}
+
+// unmount runs umount against mountpoint. Shared by every graph driver
+// since undoing a mount does not depend on how it was built.
+func unmount(mountpoint string) error {
+	cmd := exec.Command("umount", mountpoint)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("executing umount command %v. %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphdriver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	Register(&overlayDriver{})
+}
+
+// overlayDriver mounts containers managed by docker's legacy "overlay"
+// graph driver (a single lowerdir, as opposed to overlay2's arbitrary
+// chain).
+type overlayDriver struct{}
+
+func (overlayDriver) Name() string { return "overlay" }
+
+func (overlayDriver) Layers(root, id string) ([]string, error) {
+	mid, err := mountID(root, "overlay", id)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := []string{filepath.Join(root, "overlay", mid, "root")}
+
+	lowerIDPath := filepath.Join(root, "overlay", mid, "lower-id")
+	data, err := ioutil.ReadFile(lowerIDPath)
+	if err == nil {
+		lowerID := strings.TrimSpace(string(data))
+		layers = append(layers, filepath.Join(root, "overlay", lowerID, "root"))
+	}
+
+	return layers, nil
+}
+
+func (d overlayDriver) Mount(root, id, mountpoint string, readonly bool) error {
+	layers, err := d.Layers(root, id)
+	if err != nil {
+		return err
+	}
+
+	if len(layers) == 1 {
+		// Base layer: nothing to overlay, bind mount its root read-only.
+		if err := bindMountReadonly(layers[0], mountpoint); err != nil {
+			return fmt.Errorf("bind mounting overlay base layer %v", err)
+		}
+		return nil
+	}
+
+	upperdir := layers[0]
+	lowerdir := strings.Join(layers[1:], ":")
+
+	mountopts := fmt.Sprintf("ro,lowerdir=%s:%s", upperdir, lowerdir)
+	if err := mountOverlay(mountopts, mountpoint); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"lowerdir": lowerdir,
+		"upperdir": upperdir,
+	}).Debug("container overlay directories")
+
+	return nil
+}
+
+// UpperDir returns the container's own "root" directory, the writable
+// layer legacy overlay mounts on top of its single lowerdir.
+func (overlayDriver) UpperDir(root, id string) (string, error) {
+	mid, err := mountID(root, "overlay", id)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "overlay", mid, "root"), nil
+}
+
+// Data returns legacy overlay's GraphDriver.Data block: its own root
+// directory and, when the container has a parent layer, that layer's
+// root directory.
+func (d overlayDriver) Data(root, id string) (map[string]string, error) {
+	layers, err := d.Layers(root, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]string{"RootDir": layers[0]}
+	if len(layers) > 1 {
+		data["LowerDir"] = layers[1]
+	}
+	return data, nil
+}
+
+func (overlayDriver) Unmount(mountpoint string) error {
+	return unmount(mountpoint)
+}
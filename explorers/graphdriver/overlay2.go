@@ -0,0 +1,121 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphdriver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register(&overlay2Driver{})
+}
+
+// overlay2Driver mounts containers managed by docker's overlay2 graph
+// driver. This is the logic MountContainer used before graph drivers were
+// made pluggable.
+type overlay2Driver struct{}
+
+func (overlay2Driver) Name() string { return "overlay2" }
+
+// mountID resolves a container id to its mount-id for the given graph
+// driver, i.e. the directory name under <root>/<driver> that holds the
+// container's layer.
+func mountID(root, driver, id string) (string, error) {
+	mountIDPath := filepath.Join(root, "image", driver, "layerdb", "mounts", id, "mount-id")
+	data, err := ioutil.ReadFile(mountIDPath)
+	if err != nil {
+		return "", fmt.Errorf("reading container mount-id %v", err)
+	}
+	return string(data), nil
+}
+
+func (overlay2Driver) Layers(root, id string) ([]string, error) {
+	mid, err := mountID(root, "overlay2", id)
+	if err != nil {
+		return nil, err
+	}
+
+	lowerdirpath := filepath.Join(root, "overlay2", mid, "lower")
+	data, err := ioutil.ReadFile(lowerdirpath)
+	if err != nil {
+		return nil, fmt.Errorf("reading lower file %v", err)
+	}
+
+	var layers []string
+	for _, ldir := range strings.Split(string(data), ":") {
+		layers = append(layers, filepath.Join(root, "overlay2", ldir))
+	}
+	return layers, nil
+}
+
+func (d overlay2Driver) Mount(root, id, mountpoint string, readonly bool) error {
+	upperdir, err := d.UpperDir(root, id)
+	if err != nil {
+		return err
+	}
+
+	layers, err := d.Layers(root, id)
+	if err != nil {
+		return err
+	}
+	lowerdir := strings.Join(layers, ":")
+
+	// Analysts always get a read-only view: the container's writable
+	// layer is folded into the lowerdir chain rather than mounted rw,
+	// regardless of readonly, so the on-disk evidence can never be
+	// modified.
+	mountopts := fmt.Sprintf("ro,lowerdir=%s:%s", lowerdir, upperdir)
+	return mountOverlay(mountopts, mountpoint)
+}
+
+// UpperDir returns the container's overlay2 "diff" directory, its
+// writable layer.
+func (overlay2Driver) UpperDir(root, id string) (string, error) {
+	mid, err := mountID(root, "overlay2", id)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "overlay2", mid, "diff"), nil
+}
+
+// Data returns overlay2's GraphDriver.Data block: the resolved
+// LowerDir/UpperDir/WorkDir/MergedDir paths docker inspect reports.
+func (d overlay2Driver) Data(root, id string) (map[string]string, error) {
+	mid, err := mountID(root, "overlay2", id)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := d.Layers(root, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"LowerDir":  strings.Join(layers, ":"),
+		"UpperDir":  filepath.Join(root, "overlay2", mid, "diff"),
+		"WorkDir":   filepath.Join(root, "overlay2", mid, "work"),
+		"MergedDir": filepath.Join(root, "overlay2", mid, "merged"),
+	}, nil
+}
+
+func (overlay2Driver) Unmount(mountpoint string) error {
+	return unmount(mountpoint)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphdriver
+
+import "path/filepath"
+
+func init() {
+	Register(&zfsDriver{})
+}
+
+// zfsDriver mounts containers managed by docker's zfs graph driver. Like
+// btrfs, every container and image layer is its own dataset, mounted
+// under <root>/zfs/graph/<id>, so there is no layer chain to assemble:
+// mounting is a plain read-only bind mount of the container's dataset.
+type zfsDriver struct{}
+
+func (zfsDriver) Name() string { return "zfs" }
+
+func (zfsDriver) Layers(root, id string) ([]string, error) {
+	return []string{filepath.Join(root, "zfs", "graph", id)}, nil
+}
+
+func (d zfsDriver) Mount(root, id, mountpoint string, readonly bool) error {
+	layers, err := d.Layers(root, id)
+	if err != nil {
+		return err
+	}
+	return bindMountReadonly(layers[0], mountpoint)
+}
+
+// UpperDir returns the container's own dataset mountpoint: zfs has no
+// separate writable directory, the dataset itself is diffed against its
+// parent.
+func (d zfsDriver) UpperDir(root, id string) (string, error) {
+	layers, err := d.Layers(root, id)
+	if err != nil {
+		return "", err
+	}
+	return layers[0], nil
+}
+
+// Data returns zfs's GraphDriver.Data block: the dataset mountpoint
+// backing the container.
+func (d zfsDriver) Data(root, id string) (map[string]string, error) {
+	upperdir, err := d.UpperDir(root, id)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"Dataset": upperdir}, nil
+}
+
+func (zfsDriver) Unmount(mountpoint string) error {
+	return unmount(mountpoint)
+}
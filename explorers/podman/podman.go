@@ -0,0 +1,340 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package podman implements explorers.ContainerExplorer against a
+// podman/CRI-O containers/storage root directory, the layout
+// github.com/containers/storage persists containers and images through
+// rather than docker's /var/lib/docker layout.
+package podman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/images"
+	"github.com/google/container-explorer/explorers"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	storageDriverOverlay = "overlay"
+	containersFileName   = "containers.json"
+	imagesFileName       = "images.json"
+	layersFileName       = "layers.json"
+	libpodStateFileName  = "bolt_state.db"
+)
+
+type explorer struct {
+	root    string // containers/storage root directory, e.g. /var/lib/containers/storage
+	statedb *bolt.DB
+}
+
+// NewExplorer returns a ContainerExplorer for a podman/CRI-O
+// containers/storage root directory.
+func NewExplorer(root string) (explorers.ContainerExplorer, error) {
+	e := &explorer{root: root}
+
+	statedbpath := filepath.Join(root, "libpod", libpodStateFileName)
+	if fileExists(statedbpath) {
+		db, err := bolt.Open(statedbpath, 0444, &bolt.Options{ReadOnly: true})
+		if err != nil {
+			return nil, fmt.Errorf("opening libpod state database %v", err)
+		}
+		e.statedb = db
+	}
+
+	return e, nil
+}
+
+// SnapshotRoot returns the layer store directory for the overlay storage
+// driver.
+func (e *explorer) SnapshotRoot(snapshotter string) string {
+	return filepath.Join(e.root, storageDriverOverlay+"-layers")
+}
+
+// ListNamespaces returns namespaces. containers/storage has no namespace
+// concept of its own; every container and image lives in a single flat
+// store.
+func (e *explorer) ListNamespaces(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// ListContainers returns container information.
+func (e *explorer) ListContainers(ctx context.Context) ([]explorers.Container, error) {
+	storagecontainers, err := e.containers()
+	if err != nil {
+		return nil, err
+	}
+
+	var cecontainers []explorers.Container
+	for _, c := range storagecontainers {
+		var name string
+		if len(c.Names) > 0 {
+			name = c.Names[0]
+		}
+
+		meta, exposedports := parseContainerMetadata(c)
+
+		running, err := isRunning(e.statedb, c.ID)
+		if err != nil {
+			log.WithField("containerid", c.ID).Debug("resolving container running state ", err)
+		}
+
+		cecontainers = append(cecontainers, explorers.Container{
+			Hostname: meta.Config.Hostname,
+			Running:  running,
+			Container: containers.Container{
+				ID:          c.ID,
+				CreatedAt:   c.Created,
+				Image:       c.Image,
+				Snapshotter: storageDriverOverlay,
+				Runtime: containers.RuntimeInfo{
+					Name: name,
+				},
+			},
+			ExposedPorts: exposedports,
+		})
+	}
+
+	return cecontainers, nil
+}
+
+// ListImages returns image information.
+func (e *explorer) ListImages(ctx context.Context) ([]explorers.Image, error) {
+	storageimages, err := e.images()
+	if err != nil {
+		return nil, err
+	}
+
+	var ceimages []explorers.Image
+	for _, img := range storageimages {
+		var name string
+		if len(img.Names) > 0 {
+			name = img.Names[0]
+		}
+
+		ceimages = append(ceimages, explorers.Image{
+			Image: images.Image{
+				Name:      name,
+				CreatedAt: img.Created,
+				Target: ocispec.Descriptor{
+					Digest: digest.Digest(img.Digest),
+				},
+			},
+		})
+	}
+
+	return ceimages, nil
+}
+
+// ListContent is not implemented for containers/storage roots: it returns
+// no content and a nil error.
+func (e *explorer) ListContent(ctx context.Context) ([]explorers.Content, error) {
+	// TODO(container-explorer): surface containers/storage's blob store
+	// the same way the docker explorer's ListContent does.
+	return nil, nil
+}
+
+// ListSnapshots returns snapshot information, mapping containers/storage
+// "layers" to container-explorer snapshots.
+func (e *explorer) ListSnapshots(ctx context.Context) ([]explorers.SnapshotKeyInfo, error) {
+	storagelayers, err := e.layers()
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []explorers.SnapshotKeyInfo
+	for _, l := range storagelayers {
+		snapshots = append(snapshots, explorers.SnapshotKeyInfo{
+			Key:       l.ID,
+			Parent:    l.Parent,
+			CreatedAt: l.Created,
+		})
+	}
+
+	return snapshots, nil
+}
+
+// InfoContainer is not implemented for containers/storage roots: it
+// returns a nil result and a nil error.
+func (e *explorer) InfoContainer(ctx context.Context, containerid string, spec bool) (interface{}, error) {
+	// TODO(container-explorer): implement the podman/CRI-O equivalent of
+	// the docker explorer's InfoContainer, combining containers.json with
+	// the libpod state database.
+	return nil, nil
+}
+
+// DiffContainer is not implemented for containers/storage roots: it
+// returns no changes and a nil error.
+func (e *explorer) DiffContainer(ctx context.Context, containerid string) ([]explorers.Change, error) {
+	// TODO(container-explorer): implement using the same overlay whiteout
+	// walk as the docker explorer's DiffContainer.
+	return nil, nil
+}
+
+// MountContainer mounts a container to the specified path, building the
+// overlay chain from layers.json parents rather than from a single
+// driver-maintained `lower` file.
+func (e *explorer) MountContainer(ctx context.Context, containerid string, mountpoint string) error {
+	storagecontainers, err := e.containers()
+	if err != nil {
+		return err
+	}
+
+	var container *Container
+	for i := range storagecontainers {
+		if storagecontainers[i].ID == containerid {
+			container = &storagecontainers[i]
+			break
+		}
+	}
+	if container == nil {
+		return fmt.Errorf("container %s not found", containerid)
+	}
+
+	storagelayers, err := e.layers()
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]Layer, len(storagelayers))
+	for _, l := range storagelayers {
+		byID[l.ID] = l
+	}
+
+	var lowerdirs []string
+	for id := container.LayerID; id != ""; {
+		layer, ok := byID[id]
+		if !ok {
+			break
+		}
+		lowerdirs = append(lowerdirs, filepath.Join(e.root, storageDriverOverlay, layer.ID, "diff"))
+		id = layer.Parent
+	}
+	if len(lowerdirs) == 0 {
+		return fmt.Errorf("resolving overlay chain for container %s", containerid)
+	}
+
+	upperdir := filepath.Join(e.root, storageDriverOverlay+"-containers", containerid, "diff")
+
+	mountopts := fmt.Sprintf("ro,lowerdir=%s:%s", upperdir, strings.Join(lowerdirs, ":"))
+	mountargs := []string{"-t", "overlay", "overlay", "-o", mountopts, mountpoint}
+
+	cmd := exec.Command("mount", mountargs...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("executing mount command %v. %s", err, out)
+	}
+	if len(out) != 0 {
+		log.WithField("mount command", string(out)).Debug("container mount command")
+	}
+
+	return nil
+}
+
+// MountAllContainers is not implemented for containers/storage roots: it
+// mounts nothing and returns nil.
+func (e *explorer) MountAllContainers(ctx context.Context, mountpoint string, skipsupportcontainers bool) error {
+	// default
+	return nil
+}
+
+// Close releases internal resources.
+func (e *explorer) Close() error {
+	if e.statedb != nil {
+		return e.statedb.Close()
+	}
+	return nil
+}
+
+// containers reads the overlay-containers/containers.json container list.
+func (e *explorer) containers() ([]Container, error) {
+	containersfile := filepath.Join(e.root, storageDriverOverlay+"-containers", containersFileName)
+	data, err := ioutil.ReadFile(containersfile)
+	if err != nil {
+		return nil, fmt.Errorf("reading containers file %s %v", containersfile, err)
+	}
+
+	var storagecontainers []Container
+	if err := json.Unmarshal(data, &storagecontainers); err != nil {
+		return nil, fmt.Errorf("unmarshalling containers file %s %v", containersfile, err)
+	}
+	return storagecontainers, nil
+}
+
+// images reads the overlay-images/images.json image list.
+func (e *explorer) images() ([]Image, error) {
+	imagesfile := filepath.Join(e.root, storageDriverOverlay+"-images", imagesFileName)
+	data, err := ioutil.ReadFile(imagesfile)
+	if err != nil {
+		return nil, fmt.Errorf("reading images file %s %v", imagesfile, err)
+	}
+
+	var storageimages []Image
+	if err := json.Unmarshal(data, &storageimages); err != nil {
+		return nil, fmt.Errorf("unmarshalling images file %s %v", imagesfile, err)
+	}
+	return storageimages, nil
+}
+
+// layers reads the overlay-layers/layers.json layer list.
+func (e *explorer) layers() ([]Layer, error) {
+	layersfile := filepath.Join(e.root, storageDriverOverlay+"-layers", layersFileName)
+	data, err := ioutil.ReadFile(layersfile)
+	if err != nil {
+		return nil, fmt.Errorf("reading layers file %s %v", layersfile, err)
+	}
+
+	var storagelayers []Layer
+	if err := json.Unmarshal(data, &storagelayers); err != nil {
+		return nil, fmt.Errorf("unmarshalling layers file %s %v", layersfile, err)
+	}
+	return storagelayers, nil
+}
+
+// parseContainerMetadata unmarshals c's embedded docker-compatible
+// metadata document, if any, and extracts the exposed ports it carries.
+func parseContainerMetadata(c Container) (containerMetadata, []string) {
+	var meta containerMetadata
+	if c.Metadata == "" {
+		return meta, nil
+	}
+
+	if err := json.Unmarshal([]byte(c.Metadata), &meta); err != nil {
+		log.WithField("containerid", c.ID).Debug("unmarshalling container metadata ", err)
+		return containerMetadata{}, nil
+	}
+
+	var exposedports []string
+	for port := range meta.Config.ExposedPorts {
+		exposedports = append(exposedports, port)
+	}
+	return meta, exposedports
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
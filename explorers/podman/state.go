@@ -0,0 +1,83 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podman
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// containerStateRunning is libpod's define.ContainerStateRunning value, the
+// ContainerState.State a running container's bucket holds.
+//
+// Reference to libpod's ContainerStatus enum
+// https://github.com/containers/podman/blob/main/libpod/define/containerstate.go
+const containerStateRunning = 3
+
+// ctrBucketName is the top-level bucket libpod's bolt_state.db keys
+// per-container state buckets under.
+var ctrBucketName = []byte("ctr")
+
+// containerState mirrors the subset of libpod's ContainerState JSON
+// document container-explorer needs to report whether a container is
+// running.
+type containerState struct {
+	State int `json:"state"`
+}
+
+// isRunning reports whether containerid is running according to libpod's
+// state database. statedb is nil when the analyzed root has no
+// libpod/bolt_state.db, e.g. a containers/storage root used only through
+// CRI-O; isRunning then reports false without error.
+func isRunning(statedb *bolt.DB, containerid string) (bool, error) {
+	if statedb == nil {
+		return false, nil
+	}
+
+	var running bool
+	err := statedb.View(func(tx *bolt.Tx) error {
+		ctrBucket := tx.Bucket(ctrBucketName)
+		if ctrBucket == nil {
+			return fmt.Errorf("ctr bucket not found in libpod state database")
+		}
+
+		ctrCursor := ctrBucket.Bucket([]byte(containerid))
+		if ctrCursor == nil {
+			return fmt.Errorf("container %s not found in libpod state database", containerid)
+		}
+
+		data := ctrCursor.Get([]byte("state"))
+		if data == nil {
+			return fmt.Errorf("state key not found for container %s", containerid)
+		}
+
+		var state containerState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("unmarshalling container state %v", err)
+		}
+
+		running = state.State == containerStateRunning
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return running, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podman
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func openTestStateDB(t *testing.T, containers map[string]containerState) (*bolt.DB, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "podman-state-test")
+	if err != nil {
+		t.Fatalf("creating temp dir: %v", err)
+	}
+
+	db, err := bolt.Open(filepath.Join(dir, "bolt_state.db"), 0644, nil)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("opening test state database: %v", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		ctrBucket, err := tx.CreateBucketIfNotExists(ctrBucketName)
+		if err != nil {
+			return err
+		}
+		for id, state := range containers {
+			ctrCursor, err := ctrBucket.CreateBucketIfNotExists([]byte(id))
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(state)
+			if err != nil {
+				return err
+			}
+			if err := ctrCursor.Put([]byte("state"), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		os.RemoveAll(dir)
+		t.Fatalf("populating test state database: %v", err)
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestIsRunning(t *testing.T) {
+	db, cleanup := openTestStateDB(t, map[string]containerState{
+		"running-ctr": {State: containerStateRunning},
+		"exited-ctr":  {State: 4},
+	})
+	defer cleanup()
+
+	running, err := isRunning(db, "running-ctr")
+	if err != nil {
+		t.Fatalf("isRunning(running-ctr): unexpected error %v", err)
+	}
+	if !running {
+		t.Errorf("isRunning(running-ctr) = false, want true")
+	}
+
+	running, err = isRunning(db, "exited-ctr")
+	if err != nil {
+		t.Fatalf("isRunning(exited-ctr): unexpected error %v", err)
+	}
+	if running {
+		t.Errorf("isRunning(exited-ctr) = true, want false")
+	}
+
+	if _, err := isRunning(db, "missing-ctr"); err == nil {
+		t.Errorf("isRunning(missing-ctr): expected error, got nil")
+	}
+}
+
+func TestIsRunningNilDB(t *testing.T) {
+	running, err := isRunning(nil, "any-ctr")
+	if err != nil {
+		t.Fatalf("isRunning(nil): unexpected error %v", err)
+	}
+	if running {
+		t.Errorf("isRunning(nil) = true, want false")
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podman
+
+import "time"
+
+// Container mirrors the subset of containers/storage's on-disk container
+// record (overlay-containers/containers.json) container-explorer reads.
+//
+// Reference to containers/storage's Container struct
+// https://github.com/containers/storage/blob/main/store.go
+type Container struct {
+	ID       string    `json:"id"`
+	Names    []string  `json:"names,omitempty"`
+	Image    string    `json:"image"`
+	LayerID  string    `json:"layer"`
+	Metadata string    `json:"metadata,omitempty"`
+	Created  time.Time `json:"created"`
+}
+
+// containerMetadata mirrors the docker-compatible JSON document podman
+// stores in Container.Metadata, carrying the fields `podman inspect`
+// exposes that containers/storage itself does not track.
+type containerMetadata struct {
+	ImageName string `json:"image-name,omitempty"`
+	Config    struct {
+		Hostname     string              `json:"Hostname"`
+		ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	} `json:"config"`
+}
+
+// Image mirrors containers/storage's on-disk image record
+// (overlay-images/images.json).
+type Image struct {
+	ID           string    `json:"id"`
+	Names        []string  `json:"names,omitempty"`
+	Digest       string    `json:"digest,omitempty"`
+	NamesHistory []string  `json:"names-history,omitempty"`
+	TopLayer     string    `json:"layer"`
+	Created      time.Time `json:"created"`
+}
+
+// Layer mirrors containers/storage's on-disk layer record
+// (overlay-layers/layers.json).
+type Layer struct {
+	ID                 string    `json:"id"`
+	Parent             string    `json:"parent,omitempty"`
+	Names              []string  `json:"names,omitempty"`
+	MountLabel         string    `json:"mountlabel,omitempty"`
+	Created            time.Time `json:"created"`
+	CompressedDigest   string    `json:"compressed-diff-digest,omitempty"`
+	UncompressedDigest string    `json:"diff-digest,omitempty"`
+	CompressedSize     int64     `json:"compressed-size,omitempty"`
+	UncompressedSize   int64     `json:"diff-size,omitempty"`
+}
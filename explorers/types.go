@@ -0,0 +1,87 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package explorers
+
+import (
+	"time"
+
+	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/images"
+)
+
+// Container holds container information exposed by container-explorer.
+//
+// It extends containerd's containers.Container with the fields analysts
+// actually ask for that containerd's data model does not carry, such as
+// whether the container was running and which ports it exposed.
+type Container struct {
+	containers.Container
+
+	Hostname     string
+	Running      bool
+	ExposedPorts []string
+}
+
+// Image holds image information exposed by container-explorer.
+type Image struct {
+	images.Image
+
+	// History is the image's build history, oldest entry first, as
+	// recorded by the image builder.
+	History []HistoryEntry
+	// Layers is the resolved layer chain backing the image, oldest layer
+	// first.
+	Layers []LayerInfo
+}
+
+// HistoryEntry describes a single entry of an image's build history.
+type HistoryEntry struct {
+	Created     time.Time
+	CreatedBy   string
+	Comment     string
+	EmptyLayer  bool
+	Size        int64
+	LayerDigest string
+}
+
+// LayerInfo describes a single on-disk layer backing an image, resolved to
+// its snapshotter cache directory.
+type LayerInfo struct {
+	DiffID         string
+	ChainID        string
+	CacheID        string
+	Size           int64
+	ParentChainID  string
+	CompressedSize int64
+	MediaType      string
+}
+
+// Content holds information about a content blob referenced by one or more
+// images.
+type Content struct {
+	Digest    string
+	Size      int64
+	MediaType string
+	Tags      []string
+}
+
+// SnapshotKeyInfo holds information about a snapshot.
+type SnapshotKeyInfo struct {
+	Key       string
+	Parent    string
+	CreatedAt time.Time
+}
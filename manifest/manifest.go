@@ -0,0 +1,93 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package manifest parses the image manifest shapes container-explorer
+// finds on disk under image/<driver>/imagedb/content/sha256/: Docker
+// Schema 1 (signed and unsigned), Docker Schema 2 (including its manifest
+// list variant) and OCI image manifests and indexes.
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Descriptor describes a single content blob a manifest references.
+type Descriptor = ocispec.Descriptor
+
+// Manifest is the common surface exposed by every manifest shape this
+// package understands.
+type Manifest interface {
+	// MediaType returns the manifest's own media type.
+	MediaType() string
+	// Config returns the descriptor of the image configuration blob. It
+	// is the zero Descriptor for manifest lists/indexes, which have no
+	// config of their own; type assert to List and descend first.
+	Config() Descriptor
+	// Layers returns the descriptors of the image's layers, in order.
+	Layers() []Descriptor
+	// Platform returns the manifest's target platform, if known.
+	Platform() *ocispec.Platform
+}
+
+// List is additionally implemented by multi-architecture manifest lists
+// and OCI image indexes.
+type List interface {
+	Manifest
+
+	// Manifests returns the descriptors of the per-platform manifests
+	// this list references.
+	Manifests() []Descriptor
+}
+
+// probe is used to sniff a manifest blob's schemaVersion/mediaType before
+// dispatching to the matching parser.
+type probe struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	MediaType     string `json:"mediaType"`
+}
+
+// Parse parses data as an image manifest, auto-detecting which of the
+// supported shapes it is in.
+func Parse(data []byte) (Manifest, error) {
+	var p probe
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("probing manifest media type %v", err)
+	}
+
+	switch p.MediaType {
+	case MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+		return parseManifestList(data, p.MediaType)
+	case MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest:
+		return parseSchema2(data, p.MediaType)
+	case MediaTypeDockerSchema1Manifest, MediaTypeDockerSchema1SignedManifest:
+		return parseSchema1(data, p.MediaType)
+	}
+
+	// Some on-disk schema2 manifests omit mediaType. schemaVersion 2 with
+	// no recognized mediaType is still schema2; schemaVersion 1 with no
+	// mediaType is schema1.
+	switch p.SchemaVersion {
+	case 2:
+		return parseSchema2(data, MediaTypeDockerSchema2Manifest)
+	case 1:
+		return parseSchema1(data, MediaTypeDockerSchema1Manifest)
+	}
+
+	return nil, fmt.Errorf("unrecognized manifest shape (schemaVersion=%d, mediaType=%q)", p.SchemaVersion, p.MediaType)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import "testing"
+
+func TestParseSchema1(t *testing.T) {
+	data := []byte(`{
+		"schemaVersion": 1,
+		"architecture": "amd64",
+		"fsLayers": [
+			{"blobSum": "sha256:bbbb"},
+			{"blobSum": "sha256:aaaa"}
+		]
+	}`)
+
+	m, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: unexpected error %v", err)
+	}
+
+	if m.MediaType() != MediaTypeDockerSchema1Manifest {
+		t.Errorf("MediaType() = %q, want %q", m.MediaType(), MediaTypeDockerSchema1Manifest)
+	}
+
+	layers := m.Layers()
+	if len(layers) != 2 {
+		t.Fatalf("len(Layers()) = %d, want 2", len(layers))
+	}
+	// schema1 stores fsLayers newest first; Layers() must reverse them.
+	if string(layers[0].Digest) != "sha256:aaaa" || string(layers[1].Digest) != "sha256:bbbb" {
+		t.Errorf("Layers() = %+v, want oldest-first [aaaa, bbbb]", layers)
+	}
+}
+
+func TestParseSchema2(t *testing.T) {
+	data := []byte(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+		"config": {"mediaType": "application/vnd.docker.container.image.v1+json", "digest": "sha256:cccc", "size": 10},
+		"layers": [
+			{"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip", "digest": "sha256:aaaa", "size": 100}
+		]
+	}`)
+
+	m, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: unexpected error %v", err)
+	}
+
+	if string(m.Config().Digest) != "sha256:cccc" {
+		t.Errorf("Config().Digest = %q, want sha256:cccc", m.Config().Digest)
+	}
+
+	layers := m.Layers()
+	if len(layers) != 1 || string(layers[0].Digest) != "sha256:aaaa" || layers[0].Size != 100 {
+		t.Errorf("Layers() = %+v, want single aaaa/100", layers)
+	}
+
+	if _, ok := m.(List); ok {
+		t.Errorf("schema2 manifest unexpectedly implements List")
+	}
+}
+
+func TestParseManifestList(t *testing.T) {
+	data := []byte(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+		"manifests": [
+			{"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "digest": "sha256:dddd", "size": 10, "platform": {"architecture": "amd64", "os": "linux"}}
+		]
+	}`)
+
+	m, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: unexpected error %v", err)
+	}
+
+	list, ok := m.(List)
+	if !ok {
+		t.Fatalf("manifest list does not implement List")
+	}
+
+	manifests := list.Manifests()
+	if len(manifests) != 1 || string(manifests[0].Digest) != "sha256:dddd" {
+		t.Errorf("Manifests() = %+v, want single dddd", manifests)
+	}
+}
+
+func TestParseUnrecognized(t *testing.T) {
+	data := []byte(`{"schemaVersion": 3}`)
+
+	if _, err := Parse(data); err == nil {
+		t.Errorf("Parse(schemaVersion=3): expected error, got nil")
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Media types for Docker's original, now deprecated, manifest format.
+const (
+	MediaTypeDockerSchema1Manifest       = "application/vnd.docker.distribution.manifest.v1+json"
+	MediaTypeDockerSchema1SignedManifest = "application/vnd.docker.distribution.manifest.v1+prettyjws"
+)
+
+// fsLayer is a single entry of a schema1 manifest's fsLayers list.
+type fsLayer struct {
+	BlobSum string `json:"blobSum"`
+}
+
+// schema1History is a single entry of a schema1 manifest's history list,
+// carrying the full image config of the manifest as it stood at that
+// layer, serialized as a JSON string rather than a nested object.
+type schema1History struct {
+	V1Compatibility string `json:"v1Compatibility"`
+}
+
+// schema1Manifest is Docker's original manifest format. It has no single
+// config blob: image configuration is spread across the v1Compatibility
+// fragment of each history entry instead.
+type schema1Manifest struct {
+	mediaType    string
+	Architecture string           `json:"architecture"`
+	FSLayers     []fsLayer        `json:"fsLayers"`
+	History      []schema1History `json:"history"`
+}
+
+func parseSchema1(data []byte, mediaType string) (Manifest, error) {
+	var m schema1Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("unmarshalling schema1 manifest %v", err)
+	}
+	m.mediaType = mediaType
+	return &m, nil
+}
+
+func (m *schema1Manifest) MediaType() string { return m.mediaType }
+
+// Config returns the zero Descriptor: schema1 manifests carry no
+// standalone config blob. Callers needing the image config must use
+// V1Compatibility instead.
+func (m *schema1Manifest) Config() Descriptor { return Descriptor{} }
+
+// Layers returns the manifest's layers, re-ordered to be oldest first like
+// schema2 and OCI manifests; schema1 stores fsLayers newest first.
+func (m *schema1Manifest) Layers() []Descriptor {
+	layers := make([]Descriptor, len(m.FSLayers))
+	for i, l := range m.FSLayers {
+		layers[len(m.FSLayers)-1-i] = Descriptor{
+			MediaType: "application/vnd.docker.container.image.rootfs.diff+x-gtar",
+			Digest:    digest.Digest(l.BlobSum),
+		}
+	}
+	return layers
+}
+
+func (m *schema1Manifest) Platform() *ocispec.Platform {
+	if m.Architecture == "" {
+		return nil
+	}
+	return &ocispec.Platform{Architecture: m.Architecture, OS: "linux"}
+}
+
+// V1Compatibility returns m's most recent history entry's raw
+// v1Compatibility document, the closest schema1 has to a standalone image
+// config blob. ok is false for every manifest shape other than schema1,
+// since only schema1 lacks a Config() blob to fall back to.
+func V1Compatibility(m Manifest) (data []byte, ok bool) {
+	s1, ok := m.(*schema1Manifest)
+	if !ok || len(s1.History) == 0 {
+		return nil, false
+	}
+	return []byte(s1.History[0].V1Compatibility), true
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Media types for Docker Schema 2 and the equivalent OCI shapes. OCI
+// manifests/indexes are structurally identical to their Docker Schema 2
+// counterparts, so both parse through the same types.
+const (
+	MediaTypeDockerSchema2Manifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerSchema2ManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// schema2Manifest is Docker Schema 2's (and, structurally, the OCI image
+// manifest's) single-platform image manifest shape.
+type schema2Manifest struct {
+	SchemaMediaType string       `json:"mediaType"`
+	ConfigDesc      Descriptor   `json:"config"`
+	LayersDesc      []Descriptor `json:"layers"`
+}
+
+func parseSchema2(data []byte, mediaType string) (Manifest, error) {
+	var m schema2Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("unmarshalling schema2 manifest %v", err)
+	}
+	if m.SchemaMediaType == "" {
+		m.SchemaMediaType = mediaType
+	}
+	return &m, nil
+}
+
+func (m *schema2Manifest) MediaType() string    { return m.SchemaMediaType }
+func (m *schema2Manifest) Config() Descriptor   { return m.ConfigDesc }
+func (m *schema2Manifest) Layers() []Descriptor { return m.LayersDesc }
+
+// Platform is always nil: a single-platform manifest's architecture lives
+// on its config blob (the image config's "architecture"/"os" fields), not
+// on the manifest itself.
+func (m *schema2Manifest) Platform() *ocispec.Platform { return nil }
+
+// manifestList is Docker's manifest list / the OCI image index: a pointer
+// to one manifest per supported platform.
+type manifestList struct {
+	listMediaType string
+	ManifestsDesc []Descriptor `json:"manifests"`
+}
+
+func parseManifestList(data []byte, mediaType string) (Manifest, error) {
+	var l manifestList
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("unmarshalling manifest list %v", err)
+	}
+	l.listMediaType = mediaType
+	return &l, nil
+}
+
+func (l *manifestList) MediaType() string { return l.listMediaType }
+
+// Config and Layers are empty: a manifest list has no config or layers of
+// its own. Callers must type assert to List and descend into Manifests()
+// to reach a concrete, single-platform manifest first.
+func (l *manifestList) Config() Descriptor          { return Descriptor{} }
+func (l *manifestList) Layers() []Descriptor        { return nil }
+func (l *manifestList) Platform() *ocispec.Platform { return nil }
+
+// Manifests returns the descriptors of the manifests this list indexes,
+// one per supported platform.
+func (l *manifestList) Manifests() []Descriptor { return l.ManifestsDesc }